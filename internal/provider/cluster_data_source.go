@@ -6,6 +6,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/QumulusTechnology/strato-project/sdk"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -35,13 +36,21 @@ type ClusterDataSourceModel struct {
 	ControlPlaneNamespace types.String `tfsdk:"control_plane_namespace"`
 	Keypair               types.String `tfsdk:"keypair"`
 	Tags                  types.List   `tfsdk:"tags"`
+	NodePoolCount         types.Int64  `tfsdk:"node_pool_count"`
+	TotalNodeCount        types.Int64  `tfsdk:"total_node_count"`
+	ServerGroupId         types.String `tfsdk:"default_node_pool_server_group_id"`
 	Status                types.String `tfsdk:"status"`
+	Ready                 types.Bool   `tfsdk:"ready"`
 	Phase                 types.String `tfsdk:"phase"`
 	LastErrorId           types.String `tfsdk:"last_error_id"`
 	CreatedAt             types.Int64  `tfsdk:"created_at"`
 	UpdatedAt             types.Int64  `tfsdk:"updated_at"`
 	Deleted               types.Bool   `tfsdk:"deleted"`
 	DeletedAt             types.Int64  `tfsdk:"deleted_at"`
+	CreatedAtRFC3339      types.String `tfsdk:"created_at_rfc3339"`
+	UpdatedAtRFC3339      types.String `tfsdk:"updated_at_rfc3339"`
+	DeletedAtRFC3339      types.String `tfsdk:"deleted_at_rfc3339"`
+	AgeSeconds            types.Int64  `tfsdk:"age_seconds"`
 }
 
 func (d *ClusterDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -88,12 +97,28 @@ func (d *ClusterDataSource) Schema(ctx context.Context, req datasource.SchemaReq
 				MarkdownDescription: "Cluster tags",
 				Computed:            true,
 			},
+			"node_pool_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of node pools in the cluster, excluding deleted ones",
+				Computed:            true,
+			},
+			"default_node_pool_server_group_id": schema.StringAttribute{
+				MarkdownDescription: "OpenStack server group identifier of the default node pool, e.g. to audit its anti-affinity policy",
+				Computed:            true,
+			},
+			"total_node_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of node workers across all node pools of the cluster, excluding deleted ones",
+				Computed:            true,
+			},
 			"status": schema.StringAttribute{
 				MarkdownDescription: "Cluster status",
 				Computed:            true,
 			},
+			"ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether the cluster status is ready",
+				Computed:            true,
+			},
 			"phase": schema.StringAttribute{
-				MarkdownDescription: "Cluster phase",
+				MarkdownDescription: clusterPhaseDescription,
 				Computed:            true,
 			},
 			"last_error_id": schema.StringAttribute{
@@ -117,6 +142,22 @@ func (d *ClusterDataSource) Schema(ctx context.Context, req datasource.SchemaReq
 				Computed:            true,
 				Optional:            true,
 			},
+			"created_at_rfc3339": schema.StringAttribute{
+				MarkdownDescription: "Cluster created at, as an RFC 3339 timestamp",
+				Computed:            true,
+			},
+			"updated_at_rfc3339": schema.StringAttribute{
+				MarkdownDescription: "Cluster updated at, as an RFC 3339 timestamp",
+				Computed:            true,
+			},
+			"deleted_at_rfc3339": schema.StringAttribute{
+				MarkdownDescription: "Cluster deleted at, as an RFC 3339 timestamp",
+				Computed:            true,
+			},
+			"age_seconds": schema.Int64Attribute{
+				MarkdownDescription: "Cluster age in seconds, from `created_at` to the time the data source was read",
+				Computed:            true,
+			},
 		},
 	}
 }
@@ -127,17 +168,17 @@ func (d *ClusterDataSource) Configure(ctx context.Context, req datasource.Config
 		return
 	}
 
-	client, ok := req.ProviderData.(*sdk.ClientWithResponses)
+	data, ok := req.ProviderData.(*stratoDataSourceData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *sdk.ClientWithResponses, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *stratoDataSourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 func (d *ClusterDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -152,16 +193,16 @@ func (d *ClusterDataSource) Read(ctx context.Context, req datasource.ReadRequest
 
 	showResult, err := d.client.ShowClusterWithResponse(ctx, data.Id.ValueString(), &sdk.ShowClusterParams{})
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to read cluster", err.Error())
+		addAPIError(&resp.Diagnostics, "Unable to read cluster", err)
 		return
 	}
 	if showResult.StatusCode() != 200 {
-		resp.Diagnostics.AddError("Unable to read cluster", fmt.Sprintf("http response status code: %d", showResult.StatusCode()))
+		addAPIError(&resp.Diagnostics, "Unable to read cluster", newStatusError(showResult.HTTPResponse, showResult.Body))
 		return
 	}
 	cluster := showResult.JSON200
 	if cluster == nil {
-		resp.Diagnostics.AddError("Unable to read cluster", "cluster is nil")
+		addAPIError(&resp.Diagnostics, "Unable to read cluster", missingBodyError(showResult.HTTPResponse, showResult.Body, errClusterNil))
 		return
 	}
 
@@ -172,6 +213,9 @@ func (d *ClusterDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	data.ControlPlaneName = types.StringValue(cluster.ControlPlaneName)
 	data.ControlPlaneNamespace = types.StringValue(cluster.ControlPlaneNamespace)
 	data.Keypair = types.StringValue(cluster.Keypair)
+	// Note: the ShowCluster response does not report the Kubernetes version of the cluster
+	// Note: nor does it report whether the kube API is private, so private_kube_api is
+	// only known to the strato_cluster resource that set it
 	if cluster.Tags != nil {
 		listValues, diags := types.ListValueFrom(ctx, types.StringType, *cluster.Tags)
 		resp.Diagnostics.Append(diags...)
@@ -183,7 +227,9 @@ func (d *ClusterDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		data.Tags = types.ListNull(types.StringType)
 	}
 	data.Status = types.StringValue(cluster.Status)
-	data.Phase = types.StringValue(cluster.Phase)
+	data.Ready = types.BoolValue(cluster.Status == string(sdk.CLUSTER_STATUS_READY))
+	data.Phase = types.StringValue(normalizeClusterPhase(cluster.Phase))
+	warnUnknownClusterPhase(ctx, cluster.Phase)
 	data.LastErrorId = types.StringValue(cluster.LastErrorID)
 	data.CreatedAt = types.Int64Value(cluster.CreatedAt)
 	data.UpdatedAt = types.Int64Value(cluster.UpdatedAt)
@@ -193,6 +239,41 @@ func (d *ClusterDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	} else {
 		data.DeletedAt = types.Int64Null()
 	}
+	data.CreatedAtRFC3339 = epochToRFC3339(cluster.CreatedAt)
+	data.AgeSeconds = ageSeconds(cluster.CreatedAt, time.Now())
+	data.UpdatedAtRFC3339 = epochToRFC3339(cluster.UpdatedAt)
+	data.DeletedAtRFC3339 = types.StringNull()
+	if cluster.DeletedAt != nil {
+		data.DeletedAtRFC3339 = epochToRFC3339(*cluster.DeletedAt)
+	}
+
+	listResult, err := d.client.ListNodePoolsWithResponse(ctx, cluster.Id, &sdk.ListNodePoolsParams{})
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to list node pools", err)
+		return
+	}
+	if listResult.StatusCode() != 200 {
+		addAPIError(&resp.Diagnostics, "Unable to list node pools", newStatusError(listResult.HTTPResponse, listResult.Body))
+		return
+	}
+	if listResult.JSON200 == nil {
+		addAPIError(&resp.Diagnostics, "Unable to list node pools", missingBodyError(listResult.HTTPResponse, listResult.Body, errNodePoolsNil))
+		return
+	}
+	var nodePoolCount, totalNodeCount int64
+	data.ServerGroupId = types.StringNull()
+	for _, nodePool := range *listResult.JSON200 {
+		if nodePool.Deleted {
+			continue
+		}
+		if nodePool.IsDefault {
+			data.ServerGroupId = types.StringValue(nodePool.ServerGroupID)
+		}
+		nodePoolCount++
+		totalNodeCount += nodePool.NodeCount
+	}
+	data.NodePoolCount = types.Int64Value(nodePoolCount)
+	data.TotalNodeCount = types.Int64Value(totalNodeCount)
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// clusterPhase is the lifecycle phase the API reports for a cluster, next to its status.
+// The SDK types it as a plain string, the known values are the Cluster API phases the
+// backend passes through, i.e. the `status.phase` of a cluster.x-k8s.io Cluster.
+type clusterPhase string
+
+const (
+	CLUSTER_PHASE_PENDING      clusterPhase = "Pending"
+	CLUSTER_PHASE_PROVISIONING clusterPhase = "Provisioning"
+	CLUSTER_PHASE_PROVISIONED  clusterPhase = "Provisioned"
+	CLUSTER_PHASE_DELETING     clusterPhase = "Deleting"
+	CLUSTER_PHASE_FAILED       clusterPhase = "Failed"
+	CLUSTER_PHASE_UNKNOWN      clusterPhase = "Unknown"
+)
+
+// knownClusterPhases lists the phases in lifecycle order, as documented on the phase attributes.
+var knownClusterPhases = []clusterPhase{
+	CLUSTER_PHASE_PENDING,
+	CLUSTER_PHASE_PROVISIONING,
+	CLUSTER_PHASE_PROVISIONED,
+	CLUSTER_PHASE_DELETING,
+	CLUSTER_PHASE_FAILED,
+	CLUSTER_PHASE_UNKNOWN,
+}
+
+// knownClusterPhaseNames returns knownClusterPhases as strings, e.g. for validators.
+func knownClusterPhaseNames() []string {
+	names := make([]string, len(knownClusterPhases))
+	for i, phase := range knownClusterPhases {
+		names[i] = string(phase)
+	}
+
+	return names
+}
+
+// clusterPhaseDescription is the MarkdownDescription shared by the phase attributes.
+var clusterPhaseDescription = "Cluster phase, one of `" + strings.Join(knownClusterPhaseNames(), "`, `") + "`"
+
+// normalizeClusterPhase returns the known phase matching phase regardless of case,
+// or phase unchanged if the provider does not know about it.
+func normalizeClusterPhase(phase string) string {
+	for _, known := range knownClusterPhases {
+		if strings.EqualFold(phase, string(known)) {
+			return string(known)
+		}
+	}
+
+	return phase
+}
+
+// warnUnknownClusterPhase logs a phase the provider does not know about as a warning,
+// so new backend phases get noticed. It is only called once per read, not on every
+// poll of a wait.
+func warnUnknownClusterPhase(ctx context.Context, phase string) {
+	if phase == "" || slices.Contains(knownClusterPhases, clusterPhase(normalizeClusterPhase(phase))) {
+		return
+	}
+
+	tflog.Warn(ctx, "Unknown cluster phase reported by the API", map[string]any{
+		"phase": phase,
+	})
+}
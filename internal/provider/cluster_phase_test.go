@@ -0,0 +1,28 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+)
+
+func TestNormalizeClusterPhase(t *testing.T) {
+	cases := map[string]struct {
+		phase    string
+		expected string
+	}{
+		"known":   {phase: "Provisioned", expected: "Provisioned"},
+		"case":    {phase: "provisioning", expected: "Provisioning"},
+		"unknown": {phase: "Upgrading", expected: "Upgrading"},
+		"empty":   {phase: "", expected: ""},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := normalizeClusterPhase(tc.phase); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -5,16 +5,26 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/avast/retry-go/v4"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 
 	"github.com/QumulusTechnology/strato-project/sdk"
 )
@@ -22,6 +32,7 @@ import (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &ClusterResource{}
 var _ resource.ResourceWithImportState = &ClusterResource{}
+var _ resource.ResourceWithModifyPlan = &ClusterResource{}
 
 func NewClusterResource() resource.Resource {
 	return &ClusterResource{}
@@ -29,7 +40,9 @@ func NewClusterResource() resource.Resource {
 
 // ClusterResource defines the resource implementation.
 type ClusterResource struct {
-	client *sdk.ClientWithResponses
+	client         *sdk.ClientWithResponses
+	defaultKeypair string
+	polls          pollSemaphore
 }
 
 // ClusterResourceModel describes the resource data model.
@@ -47,18 +60,34 @@ type ClusterResourceModel struct {
 	// AutoScale      types.Bool  `tfsdk:"auto_scale"`
 	// MinNodeCount   types.Int64 `tfsdk:"min_node_count"`
 	// MaxNodeCount   types.Int64 `tfsdk:"max_node_count"`
-	PrivateKubeAPI types.Bool `tfsdk:"private_kube_api"`
-	Tags           types.List `tfsdk:"tags"`
+	PrivateKubeAPI     types.Bool   `tfsdk:"private_kube_api"`
+	Tags               types.List   `tfsdk:"tags"`
+	SystemTagPrefix    types.String `tfsdk:"system_tag_prefix"`
+	RefreshTrigger     types.String `tfsdk:"refresh_trigger"`
+	ChangeReason       types.String `tfsdk:"change_reason"`
+	ForceDelete        types.Bool   `tfsdk:"force_delete"`
+	StabilizeOnRead    types.Bool   `tfsdk:"stabilize_on_read"`
+	AllowErrorState    types.Bool   `tfsdk:"allow_error_state"`
+	RecreateOnError    types.Bool   `tfsdk:"recreate_on_error"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+	WaitForReady       types.Bool   `tfsdk:"wait_for_ready"`
+	WaitForPhase       types.String `tfsdk:"wait_for_phase"`
 
 	ControlPlaneName      types.String `tfsdk:"control_plane_name"`
 	ControlPlaneNamespace types.String `tfsdk:"control_plane_namespace"`
+	DefaultNodePoolId     types.String `tfsdk:"default_node_pool_id"`
+	ServerGroupId         types.String `tfsdk:"default_node_pool_server_group_id"`
 	Status                types.String `tfsdk:"status"`
+	Ready                 types.Bool   `tfsdk:"ready"`
 	Phase                 types.String `tfsdk:"phase"`
 	LastErrorId           types.String `tfsdk:"last_error_id"`
 	CreatedAt             types.Int64  `tfsdk:"created_at"`
 	UpdatedAt             types.Int64  `tfsdk:"updated_at"`
 	Deleted               types.Bool   `tfsdk:"deleted"`
 	DeletedAt             types.Int64  `tfsdk:"deleted_at"`
+	CreatedAtRFC3339      types.String `tfsdk:"created_at_rfc3339"`
+	UpdatedAtRFC3339      types.String `tfsdk:"updated_at_rfc3339"`
+	DeletedAtRFC3339      types.String `tfsdk:"deleted_at_rfc3339"`
 }
 
 func (r *ClusterResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -83,43 +112,68 @@ func (r *ClusterResource) Schema(ctx context.Context, req resource.SchemaRequest
 			"cluster_id": schema.StringAttribute{
 				MarkdownDescription: "OpenStack cluster id",
 				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"project_id": schema.StringAttribute{
 				MarkdownDescription: "OpenStack project id",
 				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "Cluster name",
-				Required:            true,
-			},
-			"keypair": schema.StringAttribute{
-				MarkdownDescription: "OpenStack keypair",
+				MarkdownDescription: "Cluster name, up to 63 lowercase letters, digits and dashes, starting and ending with a letter or digit",
 				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 63),
+					stringvalidator.RegexMatches(
+						clusterNameRegexp,
+						"must contain only lowercase letters, digits and dashes, and start and end with a letter or digit",
+					),
+				},
 			},
 
 			// required attributes but not part of the output
 			"network_id": schema.StringAttribute{
-				MarkdownDescription: "OpenStack network id",
+				MarkdownDescription: "OpenStack network id (UUID)",
 				Required:            true,
 				Computed:            false,
+				Validators:          networkIdValidators,
 			},
 			"flavor_id": schema.StringAttribute{
 				MarkdownDescription: "OpenStack flavor id",
 				Required:            true,
 				Computed:            false,
+				Validators:          flavorIdValidators,
 			},
 			"volume_size": schema.Int64Attribute{
-				MarkdownDescription: "Node worker volume size in GB",
+				MarkdownDescription: fmt.Sprintf("Node worker volume size in GB (minimum %d)", minVolumeSize),
 				Required:            true,
 				Computed:            false,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(minVolumeSize),
+				},
 			},
 			"node_count": schema.Int64Attribute{
-				MarkdownDescription: "Number of node workers",
+				MarkdownDescription: "Number of node workers. Clusters are created with at least one node, but can be scaled to zero afterwards",
 				Required:            true,
 				Computed:            false,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
 			},
 
 			// optional attributes
+			"keypair": schema.StringAttribute{
+				MarkdownDescription: "OpenStack keypair. Defaults to the provider `default_keypair`",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			// "auto_scale": schema.BoolAttribute{
 			// 	MarkdownDescription: "Cluster auto scale",
 			// 	Optional:            true,
@@ -136,15 +190,66 @@ func (r *ClusterResource) Schema(ctx context.Context, req resource.SchemaRequest
 			// 	Computed:            false,
 			// },
 			"private_kube_api": schema.BoolAttribute{
-				MarkdownDescription: "Set to true to disable public access to the kube API",
+				MarkdownDescription: "Set to true to disable public access to the kube API. The API visibility cannot be changed in place, so changing this forces a new cluster to be created",
 				Optional:            true,
 				Computed:            false,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
 			},
 			"tags": schema.ListAttribute{
 				ElementType:         types.StringType,
-				MarkdownDescription: "Cluster tags",
+				MarkdownDescription: "Cluster tags, which must be non-empty and unique. They are kept in the configured order, as the API does not preserve it",
 				Optional:            true,
 				Computed:            true,
+				Validators: []validator.List{
+					tagsValidator{},
+				},
+			},
+			"system_tag_prefix": schema.StringAttribute{
+				MarkdownDescription: "Prefix of tags added to the cluster by the platform. Such tags are left out of `tags` unless they are configured, so they do not show up as a diff on every plan",
+				Optional:            true,
+			},
+			"refresh_trigger": schema.StringAttribute{
+				MarkdownDescription: "Arbitrary value that, when changed, forces a full re-read of all computed attributes on the next apply without modifying the cluster",
+				Optional:            true,
+			},
+			"change_reason": schema.StringAttribute{
+				MarkdownDescription: changeReasonDescription,
+				Optional:            true,
+				WriteOnly:           true,
+			},
+			"force_delete": schema.BoolAttribute{
+				MarkdownDescription: "Set to true to return as soon as the delete request is accepted instead of waiting for the cluster to be deleted. Must be applied before running destroy",
+				Optional:            true,
+			},
+			"stabilize_on_read": schema.BoolAttribute{
+				MarkdownDescription: fmt.Sprintf("Set to true to have refresh wait up to %d seconds for a cluster that is in progress to settle, instead of storing the transitional status", stabilizeOnReadAttempts*10),
+				Optional:            true,
+			},
+
+			"wait_for_ready": schema.BoolAttribute{
+				MarkdownDescription: "Set to false to return as soon as the create request is accepted instead of waiting for the cluster to be ready. The status is then whatever the API reports right after the request. Defaults to true",
+				Optional:            true,
+			},
+			"wait_for_phase": schema.StringAttribute{
+				MarkdownDescription: "Phase that, once reached, ends the wait on create or on a resize even though the cluster or its default node pool is still in progress, e.g. `Provisioned`. The cluster becoming ready ends the wait too",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(knownClusterPhaseNames()...),
+				},
+			},
+			"allow_error_state": schema.BoolAttribute{
+				MarkdownDescription: "Set to true to keep a cluster that ends up in error state during create in state with a warning, so `last_error_id` and `phase` can be inspected, instead of failing the apply",
+				Optional:            true,
+			},
+			"recreate_on_error": schema.BoolAttribute{
+				MarkdownDescription: "Set to true to have create delete a cluster that ends up in error state and create it again, once, before giving up",
+				Optional:            true,
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Set to true to have the provider refuse to delete the cluster, including when it has to be replaced. Must be set back to false and applied before the cluster can be destroyed",
+				Optional:            true,
 			},
 
 			// output-only attributes
@@ -156,12 +261,30 @@ func (r *ClusterResource) Schema(ctx context.Context, req resource.SchemaRequest
 				MarkdownDescription: "Cluster control plane namespace",
 				Computed:            true,
 			},
+			"default_node_pool_id": schema.StringAttribute{
+				MarkdownDescription: "Identifier of the node pool created along with the cluster",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"default_node_pool_server_group_id": schema.StringAttribute{
+				MarkdownDescription: "OpenStack server group identifier of the default node pool, e.g. to audit its anti-affinity policy",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"status": schema.StringAttribute{
 				MarkdownDescription: "Cluster status",
 				Computed:            true,
 			},
+			"ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether the cluster status is ready",
+				Computed:            true,
+			},
 			"phase": schema.StringAttribute{
-				MarkdownDescription: "Cluster phase",
+				MarkdownDescription: clusterPhaseDescription,
 				Computed:            true,
 			},
 			"last_error_id": schema.StringAttribute{
@@ -185,6 +308,18 @@ func (r *ClusterResource) Schema(ctx context.Context, req resource.SchemaRequest
 				Computed:            true,
 				Optional:            true,
 			},
+			"created_at_rfc3339": schema.StringAttribute{
+				MarkdownDescription: "Cluster created at, as an RFC 3339 timestamp",
+				Computed:            true,
+			},
+			"updated_at_rfc3339": schema.StringAttribute{
+				MarkdownDescription: "Cluster updated at, as an RFC 3339 timestamp",
+				Computed:            true,
+			},
+			"deleted_at_rfc3339": schema.StringAttribute{
+				MarkdownDescription: "Cluster deleted at, as an RFC 3339 timestamp",
+				Computed:            true,
+			},
 		},
 	}
 }
@@ -195,18 +330,43 @@ func (r *ClusterResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*sdk.ClientWithResponses)
+	data, ok := req.ProviderData.(*stratoResourceData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *sdk.ClientWithResponses, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *stratoResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.defaultKeypair = data.defaultKeypair
+	r.polls = data.polls
+}
+
+// ModifyPlan rejects a node_count of zero for new clusters at plan time. Scale-to-zero
+// is only supported on existing clusters, the default node pool needs a node to bootstrap.
+func (r *ClusterResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy or for existing clusters
+	if req.Plan.Raw.IsNull() || !req.State.Raw.IsNull() {
+		return
+	}
+
+	var nodeCount types.Int64
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("node_count"), &nodeCount)...)
+	if resp.Diagnostics.HasError() || nodeCount.IsNull() || nodeCount.IsUnknown() {
+		return
+	}
+
+	if nodeCount.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("node_count"),
+			"Invalid node count",
+			"A cluster must be created with at least one node. Set node_count to 0 once it exists to scale it to zero.",
+		)
+	}
 }
 
 func (r *ClusterResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -219,6 +379,23 @@ func (r *ClusterResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	var changeReason types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("change_reason"), &changeReason)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keypair := resolveKeypair(data.Keypair, r.defaultKeypair)
+	if keypair == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("keypair"),
+			"Missing keypair",
+			"Set keypair on the resource or default_keypair on the provider.",
+		)
+		return
+	}
+
 	// Can skip Authorization header since its handled by client options in provider configuration
 	// But we must set X-OS-Cluster-ID and X-OS-Project-ID headers via params
 	params := &sdk.CreateClusterParams{
@@ -230,7 +407,7 @@ func (r *ClusterResource) Create(ctx context.Context, req resource.CreateRequest
 		NodeCount:  data.NodeCount.ValueInt64(),
 		FlavorID:   data.FlavorId.ValueString(),
 		NetworkID:  data.NetworkId.ValueString(),
-		Keypair:    data.Keypair.ValueString(),
+		Keypair:    keypair,
 		VolumeSize: data.VolumeSize.ValueInt64(),
 	}
 	// if !data.AutoScale.IsUnknown() && !data.AutoScale.IsNull() {
@@ -255,63 +432,154 @@ func (r *ClusterResource) Create(ctx context.Context, req resource.CreateRequest
 	if !data.PrivateKubeAPI.IsUnknown() && !data.PrivateKubeAPI.IsNull() {
 		body.PrivateKubeAPI = &[]bool{data.PrivateKubeAPI.ValueBool()}[0]
 	}
+	// Note: CreateClusterRequestBody only takes tags as plain strings, it has no key/value
+	// labels, authorized networks or Kubernetes version field
 
-	createResult, err := r.client.CreateClusterWithResponse(ctx, params, body)
+	id, err := r.requestCluster(ctx, params, body, changeReason)
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to create cluster", err.Error())
+		addAPIError(&resp.Diagnostics, "Unable to create cluster", err)
 		return
 	}
-	if createResult.StatusCode() != 200 {
-		// Try to extract error message from response body
-		errorMsg := fmt.Sprintf("HTTP %d", createResult.StatusCode())
-		if len(createResult.Body) > 0 {
-			errorMsg = fmt.Sprintf("HTTP %d: %s", createResult.StatusCode(), string(createResult.Body))
+
+	// The default node pool is looked up once the wait is over, not on every poll
+	data.DefaultNodePoolId = types.StringNull()
+	data.ServerGroupId = types.StringNull()
+
+	if !waitForReady(data.WaitForReady) {
+		if err := r.readCluster(ctx, id, &data); err != nil {
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+			addAPIError(&resp.Diagnostics, "Unable to create cluster", err)
+			return
 		}
-		resp.Diagnostics.AddError("Unable to create cluster", errorMsg)
+		if err := r.lookupDefaultNodePool(ctx, id, &data); err != nil {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			addAPIError(&resp.Diagnostics, "Unable to create cluster", err)
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
-	if createResult.JSON200 == nil {
-		resp.Diagnostics.AddError("Unable to create cluster", "cluster is nil")
+
+	// Calculate timeout based on node count (10-20 minutes)
+	attempts := calculateRetryAttempts(data.NodeCount.ValueInt64(), defaultBaseTimeout, defaultLargeClusterIncrement)
+	clusterRead, err := r.waitForClusterReady(ctx, id, attempts, &data)
+
+	// Give a cluster that ended up in error state a single second chance
+	if err != nil && clusterRead && data.RecreateOnError.ValueBool() && data.Status.ValueString() == string(sdk.CLUSTER_STATUS_ERROR) {
+		tflog.Warn(ctx, "Cluster ended up in error state, deleting it to create it again", map[string]any{
+			"id":            id,
+			"phase":         data.Phase.ValueString(),
+			"last_error_id": data.LastErrorId.ValueString(),
+		})
+
+		if err := r.deleteCluster(ctx, id); err != nil {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			addAPIError(&resp.Diagnostics, "Unable to delete cluster in error state", err)
+			return
+		}
+		if err := r.waitForClusterDeleted(ctx, id); err != nil {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			addAPIError(&resp.Diagnostics, "Unable to delete cluster in error state", err)
+			return
+		}
+
+		id, err = r.requestCluster(ctx, params, body, changeReason)
+		if err != nil {
+			addAPIError(&resp.Diagnostics, "Unable to create cluster", err)
+			return
+		}
+		tflog.Info(ctx, "Cluster created again after ending up in error state", map[string]any{
+			"id":          id,
+			"previous_id": data.Id.ValueString(),
+		})
+		clusterRead, err = r.waitForClusterReady(ctx, id, attempts, &data)
+	}
+
+	if err != nil && clusterRead && data.AllowErrorState.ValueBool() && data.Status.ValueString() == string(sdk.CLUSTER_STATUS_ERROR) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		resp.Diagnostics.AddWarning(
+			"Cluster is in error state",
+			fmt.Sprintf("Cluster %s was created but ended up in error state (phase %q, last error id %q). "+
+				"It is kept in state for inspection, taint or destroy it to create it again.",
+				data.Id.ValueString(), data.Phase.ValueString(), data.LastErrorId.ValueString()),
+		)
 		return
 	}
 
-	// Calculate timeout based on node count (10-20 minutes)
-	attempts := calculateRetryAttempts(data.NodeCount.ValueInt64())
+	if err != nil {
+		// The cluster exists even though it never became ready. Keep track of it in state
+		// (Terraform marks it as tainted) so a subsequent apply can reconcile or delete it.
+		if clusterRead {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		} else {
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+		}
+		addAPIError(&resp.Diagnostics, "Unable to create cluster", err)
+		return
+	}
 
-	err = retry.Do(
-		func() error {
-			if err := r.readCluster(ctx, createResult.JSON200.Id, &data); err != nil {
+	if err := r.lookupDefaultNodePool(ctx, id, &data); err != nil {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		addAPIError(&resp.Diagnostics, "Unable to create cluster", err)
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// requestCluster sends the create request and returns the id of the new cluster.
+func (r *ClusterResource) requestCluster(ctx context.Context, params *sdk.CreateClusterParams, body sdk.CreateClusterJSONRequestBody, changeReason types.String) (string, error) {
+	createResult, err := r.client.CreateClusterWithResponse(ctx, params, body, changeReasonEditor(changeReason))
+	if err != nil {
+		return "", err
+	}
+	if createResult.StatusCode() != 200 {
+		return "", newStatusError(createResult.HTTPResponse, createResult.Body)
+	}
+	if createResult.JSON200 == nil {
+		return "", missingBodyError(createResult.HTTPResponse, createResult.Body, errClusterNil)
+	}
+
+	return createResult.JSON200.Id, nil
+}
+
+// waitForClusterReady polls the cluster into data until it leaves the in progress
+// status. It reports whether the cluster could be read at all, as data is only
+// meaningful then.
+func (r *ClusterResource) waitForClusterReady(ctx context.Context, id string, attempts uint, data *ClusterResourceModel) (bool, error) {
+	clusterRead := false
+	notFound := 0
+	statuses := newStatusLogger("Cluster", id)
+	progress := newPollProgress()
+
+	err := pollCluster(ctx, r.polls, attempts,
+		func(ctx context.Context) error {
+			progress.attempt()
+			if err := r.readCluster(ctx, id, data); err != nil {
 				return err
 			}
-			switch data.Status.ValueString() {
-			case string(sdk.CLUSTER_STATUS_IN_PROGRESS):
-				return fmt.Errorf("cluster is in progress")
-			case string(sdk.CLUSTER_STATUS_ERROR):
-				return fmt.Errorf("cluster is in error state")
-			case string(sdk.CLUSTER_STATUS_DELETING):
-				return fmt.Errorf("cluster is in deleting state")
-			case string(sdk.CLUSTER_STATUS_READY):
+			clusterRead = true
+			statuses.observe(ctx, data.Status.ValueString())
+			err := clusterStatusError(data.Status.ValueString())
+			if phase := data.WaitForPhase.ValueString(); errors.Is(err, errClusterInProgress) && phase != "" && data.Phase.ValueString() == phase {
 				return nil
-			default:
-				return fmt.Errorf("cluster is in unknown state")
 			}
+			return err
+		},
+		func(err error) bool {
+			// A cluster that was just created can be unknown to ShowCluster until its
+			// record propagates, once it has been read a 404 means it is really gone
+			if !clusterRead && isNotFound(err) {
+				notFound++
+				return notFound <= maxNotFoundClusterRetries
+			}
+			return false
 		},
-		retry.Context(ctx),
-		retry.Delay(10*time.Second),
-		retry.DelayType(retry.FixedDelay),
-		retry.Attempts(attempts),
-		retry.RetryIf(func(err error) bool {
-			return err != nil && err.Error() == "cluster is in progress"
-		}),
 	)
 
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to create cluster", err.Error())
-		return
-	}
-
-	// Save data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	return clusterRead, progress.timeoutError(err, attempts)
 }
 
 func (r *ClusterResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -324,111 +592,255 @@ func (r *ClusterResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	if err := r.readCluster(ctx, data.Id.ValueString(), &data); err != nil {
-		resp.Diagnostics.AddError("Unable to read cluster", err.Error())
+	attempts := uint(1)
+	if data.StabilizeOnRead.ValueBool() {
+		attempts = stabilizeOnReadAttempts
+	}
+
+	err := retry.Do(
+		func() error {
+			if err := r.readCluster(ctx, data.Id.ValueString(), &data); err != nil {
+				return err
+			}
+			if data.Status.ValueString() == string(sdk.CLUSTER_STATUS_IN_PROGRESS) {
+				return errClusterInProgress
+			}
+			return nil
+		},
+		retry.Context(ctx),
+		retry.Delay(pollInterval),
+		retry.DelayType(retry.FixedDelay),
+		retry.Attempts(attempts),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(func(err error) bool {
+			return errors.Is(err, errClusterInProgress)
+		}),
+	)
+
+	// A cluster still in progress after the wait is stored as is
+	if err != nil && !errors.Is(err, errClusterInProgress) {
+		addAPIError(&resp.Diagnostics, "Unable to read cluster", err)
 		return
 	}
+	warnUnknownClusterPhase(ctx, data.Phase.ValueString())
+
+	if err := r.lookupDefaultNodePool(ctx, data.Id.ValueString(), &data); err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to read cluster", err)
+		return
+	}
+
+	// After import only the id is known, recover the inputs ShowCluster does not return
+	if data.NetworkId.IsNull() {
+		if err := r.readDefaultNodePool(ctx, data.Id.ValueString(), &data); err != nil {
+			addAPIError(&resp.Diagnostics, "Unable to read cluster", err)
+			return
+		}
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *ClusterResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data ClusterResourceModel
+	var data, state ClusterResourceModel
 
-	// Read Terraform plan data into the model
+	// Read Terraform plan and prior state data into the models
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	listResult, err := r.client.ListNodePoolsWithResponse(ctx, data.Id.ValueString(), &sdk.ListNodePoolsParams{
-		OnlyDefault: &[]bool{true}[0],
-	})
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to list default node pool", err.Error())
-		return
-	}
-	if listResult.StatusCode() != 200 {
-		resp.Diagnostics.AddError("Unable to list default node pool", fmt.Sprintf("http response status code: %d", listResult.StatusCode()))
+	var changeReason types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("change_reason"), &changeReason)...)
+
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	if listResult.JSON200 == nil {
-		resp.Diagnostics.AddError("Unable to list default node pool", "node pools is nil")
+
+	// Note: the API has no cluster upgrade endpoint and no Kubernetes version field, so
+	// node_count is the only attribute updated in place; version upgrades are not supported
+	// Note: UpdateClusterRequestBody only carries the node count, so scaling down cannot
+	// ask for nodes to be drained first (see the node pool resource)
+
+	// Nothing to send to the API (e.g. only refresh_trigger changed), just re-read the cluster
+	if data.NodeCount.Equal(state.NodeCount) {
+		if err := r.readCluster(ctx, data.Id.ValueString(), &data); err != nil {
+			addAPIError(&resp.Diagnostics, "Unable to update cluster", err)
+			return
+		}
+		if err := r.lookupDefaultNodePool(ctx, data.Id.ValueString(), &data); err != nil {
+			addAPIError(&resp.Diagnostics, "Unable to update cluster", err)
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
-	if len(*listResult.JSON200) == 0 {
-		resp.Diagnostics.AddError("Unable to list default node pool", "no node pools found")
+
+	// The default node pool can briefly be missing from the list, or the list be refused,
+	// while another operation runs on the cluster: retry a few times before giving up
+	var listResult *sdk.ListNodePoolsResponse
+	defaultIndex := -1
+	err := retry.Do(
+		func() error {
+			ctx, cancel := context.WithTimeout(ctx, pollRequestTimeout)
+			defer cancel()
+
+			var err error
+			listResult, err = r.client.ListNodePoolsWithResponse(ctx, data.Id.ValueString(), &sdk.ListNodePoolsParams{
+				OnlyDefault: &[]bool{true}[0],
+			})
+			if err != nil {
+				return err
+			}
+			if listResult.StatusCode() != 200 {
+				return newStatusError(listResult.HTTPResponse, listResult.Body)
+			}
+			if listResult.JSON200 == nil {
+				return missingBodyError(listResult.HTTPResponse, listResult.Body, errNodePoolsNil)
+			}
+			// Don't rely on OnlyDefault being honoured, resizing any other pool would be wrong
+			for i, nodePool := range *listResult.JSON200 {
+				if nodePool.IsDefault {
+					defaultIndex = i
+					return nil
+				}
+			}
+			return errDefaultNodePoolMissing
+		},
+		retry.Context(ctx),
+		retry.DelayType(retry.FixedDelay),
+		retry.Delay(pollInterval),
+		retry.Attempts(listNodePoolsAttempts),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(transientListError),
+	)
+	if err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to list default node pool", err)
 		return
 	}
-	defaultNodePool := (*listResult.JSON200)[0]
+	defaultNodePool := (*listResult.JSON200)[defaultIndex]
+	data.ServerGroupId = types.StringValue(defaultNodePool.ServerGroupID)
 
 	params := &sdk.UpdateClusterParams{}
 	body := sdk.UpdateClusterJSONRequestBody{
 		NodeCount: data.NodeCount.ValueInt64(),
 	}
-	updateResult, err := r.client.UpdateClusterWithResponse(ctx, data.Id.ValueString(), params, body)
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to update cluster", err.Error())
+	// The cluster can be busy with another operation, e.g. on one of its node pools
+	var updateResult *sdk.UpdateClusterResponse
+	err = retry.Do(
+		func() error {
+			var err error
+			updateResult, err = r.client.UpdateClusterWithResponse(ctx, data.Id.ValueString(), params, body, changeReasonEditor(changeReason))
+			if err != nil {
+				return err
+			}
+			if isClusterBusy(updateResult.StatusCode(), updateResult.Body) {
+				return errClusterBusy
+			}
+			return nil
+		},
+		retry.Context(ctx),
+		retry.DelayType(retry.FixedDelay),
+		retry.Delay(pollInterval),
+		retry.Attempts(clusterBusyAttempts),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(func(err error) bool {
+			return errors.Is(err, errClusterBusy)
+		}),
+	)
+	// A conflict that outlasted the wait is reported with its response body below
+	if err != nil && !errors.Is(err, errClusterBusy) {
+		addAPIError(&resp.Diagnostics, "Unable to update cluster", err)
 		return
 	}
 	if updateResult.StatusCode() != 200 {
-		resp.Diagnostics.AddError("Unable to update cluster", fmt.Sprintf("http response status code: %d", updateResult.StatusCode()))
+		addAPIError(&resp.Diagnostics, "Unable to update cluster", newStatusError(updateResult.HTTPResponse, updateResult.Body))
 		return
 	}
 	if updateResult.JSON200 == nil {
-		resp.Diagnostics.AddError("Unable to update cluster", "cluster is nil")
+		addAPIError(&resp.Diagnostics, "Unable to update cluster", missingBodyError(updateResult.HTTPResponse, updateResult.Body, errClusterNil))
 		return
 	}
 
 	// watch for resizing update if node count is different
 	if defaultNodePool.NodeCount != data.NodeCount.ValueInt64() {
-		// Calculate timeout based on new node count (10-20 minutes)
-		attempts := calculateRetryAttempts(data.NodeCount.ValueInt64())
+		// Calculate timeout based on the larger of the old and new node count (10-20 minutes),
+		// draining a large pool down to zero takes as long as growing it
+		attempts := calculateRetryAttempts(max(defaultNodePool.NodeCount, data.NodeCount.ValueInt64()), defaultBaseTimeout, defaultLargeClusterIncrement)
+		statuses := newStatusLogger("Node pool", defaultNodePool.Id)
+		progress := newPollProgress()
 
 		err = retry.Do(
 			func() error {
-				showResult, err := r.client.ShowNodePoolWithResponse(ctx, defaultNodePool.ClusterID, defaultNodePool.Id, &sdk.ShowNodePoolParams{})
+				progress.attempt()
+				release, err := r.polls.acquire(ctx)
+				if err != nil {
+					return err
+				}
+				defer release()
+				requestCtx, cancel := context.WithTimeout(ctx, pollRequestTimeout)
+				defer cancel()
+
+				showResult, err := r.client.ShowNodePoolWithResponse(requestCtx, defaultNodePool.ClusterID, defaultNodePool.Id, &sdk.ShowNodePoolParams{})
 				if err != nil {
 					return err
 				}
 				if showResult.StatusCode() != 200 {
-					return fmt.Errorf("http response status code: %d", showResult.StatusCode())
+					return newStatusError(showResult.HTTPResponse, showResult.Body)
 				}
 				if showResult.JSON200 == nil {
-					return fmt.Errorf("node pool is nil")
+					return missingBodyError(showResult.HTTPResponse, showResult.Body, errNodePoolNil)
 				}
+				statuses.observe(ctx, showResult.JSON200.Status)
 				switch showResult.JSON200.Status {
 				case string(sdk.NODE_POOL_STATUS_RESIZING):
+					// The phase is reported on the cluster, not on the node pool
+					if phase := data.WaitForPhase.ValueString(); phase != "" {
+						if err := r.readCluster(ctx, data.Id.ValueString(), &data); err != nil {
+							return err
+						}
+						if data.Phase.ValueString() == phase {
+							return nil
+						}
+					}
 					return fmt.Errorf("node pool is in resizing state")
 				case string(sdk.NODE_POOL_STATUS_ERROR):
 					return fmt.Errorf("node pool is in error state")
 				case string(sdk.NODE_POOL_STATUS_DELETING):
 					return fmt.Errorf("node pool is in deleting state")
 				case string(sdk.NODE_POOL_STATUS_READY):
+					// A pool scaled to zero is READY without any nodes
 					return nil
 				default:
 					return fmt.Errorf("node pool is in unknown state")
 				}
 			},
 			retry.Context(ctx),
-			retry.Delay(10*time.Second),
+			retry.Delay(pollInterval),
 			retry.DelayType(retry.FixedDelay),
 			retry.Attempts(attempts),
+			retry.LastErrorOnly(true),
 			retry.RetryIf(func(err error) bool {
 				return err != nil && err.Error() == "node pool is in resizing state"
 			}),
 		)
+		err = progress.timeoutError(err, attempts)
 	}
 
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to update cluster", err.Error())
+		addAPIError(&resp.Diagnostics, "Unable to update cluster", err)
 		return
 	}
 
 	if err := r.readCluster(ctx, data.Id.ValueString(), &data); err != nil {
-		resp.Diagnostics.AddError("Unable to update cluster", err.Error())
+		addAPIError(&resp.Diagnostics, "Unable to update cluster", err)
+		return
+	}
+	if err := r.lookupDefaultNodePool(ctx, data.Id.ValueString(), &data); err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to update cluster", err)
 		return
 	}
 
@@ -446,24 +858,63 @@ func (r *ClusterResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	deleteResult, err := r.client.DeleteClusterWithResponse(ctx, data.Id.ValueString(), &sdk.DeleteClusterParams{}, sdk.DeleteClusterRequestBody{})
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to delete cluster", err.Error())
+	if data.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Cluster is protected from deletion",
+			fmt.Sprintf("Cluster %s has deletion_protection set. Set it to false and apply before destroying or replacing the cluster.", data.Id.ValueString()),
+		)
 		return
 	}
-	if deleteResult.StatusCode() >= 400 {
-		resp.Diagnostics.AddError("Unable to delete cluster", fmt.Sprintf("http response status code: %d", deleteResult.StatusCode()))
+
+	if err := r.deleteCluster(ctx, data.Id.ValueString()); err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to delete cluster", err)
 		return
 	}
-	if deleteResult.JSON200 == nil {
-		resp.Diagnostics.AddError("Unable to delete cluster", "cluster is nil")
+
+	// Don't wait for the cluster to disappear, e.g. when it is stuck in error state
+	if data.ForceDelete.ValueBool() {
 		return
 	}
 
-	// Use 10 minute timeout for deletion (independent of node count)
-	err = retry.Do(
+	if err := r.waitForClusterDeleted(ctx, data.Id.ValueString()); err != nil {
+		addAPIError(&resp.Diagnostics, "Unable to delete cluster", err)
+		return
+	}
+}
+
+// deleteCluster sends the delete request for the cluster.
+func (r *ClusterResource) deleteCluster(ctx context.Context, id string) error {
+	deleteResult, err := r.client.DeleteClusterWithResponse(ctx, id, &sdk.DeleteClusterParams{}, sdk.DeleteClusterRequestBody{})
+	if err != nil {
+		return err
+	}
+	if deleteResult.StatusCode() >= 400 {
+		return newStatusError(deleteResult.HTTPResponse, deleteResult.Body)
+	}
+	if deleteResult.JSON200 == nil {
+		return missingBodyError(deleteResult.HTTPResponse, deleteResult.Body, errClusterNil)
+	}
+
+	return nil
+}
+
+// waitForClusterDeleted polls the cluster until it is gone.
+func (r *ClusterResource) waitForClusterDeleted(ctx context.Context, id string) error {
+	statuses := newStatusLogger("Cluster", id)
+	progress := newPollProgress()
+
+	err := retry.Do(
 		func() error {
-			showResult, err := r.client.ShowClusterWithResponse(ctx, data.Id.ValueString(), &sdk.ShowClusterParams{})
+			progress.attempt()
+			release, err := r.polls.acquire(ctx)
+			if err != nil {
+				return err
+			}
+			defer release()
+			ctx, cancel := context.WithTimeout(ctx, pollRequestTimeout)
+			defer cancel()
+
+			showResult, err := r.client.ShowClusterWithResponse(ctx, id, &sdk.ShowClusterParams{})
 			if err != nil {
 				return err
 			}
@@ -471,14 +922,15 @@ func (r *ClusterResource) Delete(ctx context.Context, req resource.DeleteRequest
 				return nil
 			}
 			if showResult.StatusCode() != 200 {
-				return fmt.Errorf("http response status code: %d", showResult.StatusCode())
+				return newStatusError(showResult.HTTPResponse, showResult.Body)
 			}
 			if showResult.JSON200 == nil {
-				return fmt.Errorf("cluster is nil")
+				return missingBodyError(showResult.HTTPResponse, showResult.Body, errClusterNil)
 			}
 			if showResult.JSON200.Deleted {
 				return nil
 			}
+			statuses.observe(ctx, showResult.JSON200.Status)
 			switch showResult.JSON200.Status {
 			case string(sdk.CLUSTER_STATUS_IN_PROGRESS):
 				return fmt.Errorf("cluster is in progress")
@@ -487,55 +939,428 @@ func (r *ClusterResource) Delete(ctx context.Context, req resource.DeleteRequest
 			case string(sdk.CLUSTER_STATUS_DELETING):
 				return fmt.Errorf("cluster is in deleting state")
 			case string(sdk.CLUSTER_STATUS_READY):
-				return nil
+				// The delete request may not have been picked up yet, or was ignored
+				return fmt.Errorf("cluster is still ready after the delete request")
 			default:
 				return fmt.Errorf("cluster is in unknown state")
 			}
 		},
 		retry.Context(ctx),
 		retry.DelayType(retry.FixedDelay),
-		retry.Delay(10*time.Second),
-		retry.Attempts(60), // 10 minutes
-		retry.RetryIf(func(err error) bool {
-			return err != nil && err.Error() == "cluster is in deleting state"
-		}),
+		retry.Delay(pollInterval),
+		retry.Attempts(deletePollAttempts),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(nilClusterRetryIf(func(err error) bool {
+			return err != nil && (err.Error() == "cluster is in deleting state" || err.Error() == "cluster is still ready after the delete request")
+		})),
 	)
 
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to delete cluster", err.Error())
-		return
-	}
+	return progress.timeoutError(err, deletePollAttempts)
 }
 
 func (r *ClusterResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Note: the API has no cluster listing endpoint, so a project_id:cluster_name composite
+	// cannot be resolved to an id; say so rather than failing later with a 404
+	if strings.Contains(req.ID, ":") {
+		resp.Diagnostics.AddError(
+			"Unsupported import identifier",
+			fmt.Sprintf("Clusters can only be imported by id, %q looks like a project_id:cluster_name composite, which the Strato API cannot resolve.", req.ID),
+		)
+		return
+	}
+
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-// calculateRetryAttempts calculates the number of retry attempts based on node count.
-// Provides 10 minutes for small clusters (≤3 nodes), 20 minutes for larger clusters.
-func calculateRetryAttempts(nodeCount int64) uint {
-	// Base: 10 minutes = 60 attempts × 10 seconds
-	baseAttempts := uint(60)
+// clusterNameRegexp matches the DNS label style names accepted by the API.
+var clusterNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// uuidRegexp matches the UUIDs OpenStack uses to identify networks.
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// flavorIdRegexp matches OpenStack flavor ids. These are usually UUIDs, but
+// operators can create flavors with custom ids such as "m1.small".
+var flavorIdRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// networkIdValidators reject network names passed where an id is expected.
+var networkIdValidators = []validator.String{
+	stringvalidator.RegexMatches(uuidRegexp, "must be an OpenStack network UUID, not a network name"),
+}
+
+// flavorIdValidators reject values that cannot be a flavor id, such as display
+// names containing spaces.
+var flavorIdValidators = []validator.String{
+	stringvalidator.RegexMatches(flavorIdRegexp, "must be an OpenStack flavor id: a UUID or letters, digits, dots, dashes and underscores"),
+}
+
+// minVolumeSize is the smallest node volume size in GB accepted by the platform.
+const minVolumeSize = 10
+
+// changeReasonDescription documents the change_reason attribute shared by the resources.
+const changeReasonDescription = "Reason for the change, sent as the `X-Change-Reason` header on create and update requests so it is recorded in the audit log. Changing it alone does not trigger an update"
+
+// changeReasonEditor returns a request editor that sets the X-Change-Reason header
+// to reason. A null or empty reason leaves the request untouched.
+func changeReasonEditor(reason types.String) sdk.RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		if reason.ValueString() != "" {
+			req.Header.Set("X-Change-Reason", reason.ValueString())
+		}
+		return nil
+	}
+}
+
+// waitForReady reports whether a create or update should wait for the object to be
+// ready, which is the default when the wait_for_ready attribute is unset.
+func waitForReady(value types.Bool) bool {
+	return value.IsNull() || value.IsUnknown() || value.ValueBool()
+}
 
-	// Add 10 more minutes (60 attempts) for clusters with more than 3 nodes
-	if nodeCount > 3 {
-		return baseAttempts + 60 // 20 minutes total
+// epochToRFC3339 formats a Unix timestamp returned by the API as an RFC 3339 string,
+// or null when the API left it unset (zero).
+func epochToRFC3339(seconds int64) types.String {
+	if seconds == 0 {
+		return types.StringNull()
 	}
 
-	return baseAttempts // 10 minutes
+	return types.StringValue(time.Unix(seconds, 0).UTC().Format(time.RFC3339))
 }
 
-func (r *ClusterResource) readCluster(ctx context.Context, id string, data *ClusterResourceModel) error {
-	params := &sdk.ShowClusterParams{}
-	result, err := r.client.ShowClusterWithResponse(ctx, id, params)
+// ageSeconds returns how many seconds have passed between the Unix timestamp
+// createdAt and now, or null when the API left createdAt unset (zero).
+func ageSeconds(createdAt int64, now time.Time) types.Int64 {
+	if createdAt == 0 {
+		return types.Int64Null()
+	}
+
+	return types.Int64Value(max(now.Unix()-createdAt, 0))
+}
+
+// resolveKeypair returns the configured keypair, or defaultKeypair when it is not set.
+func resolveKeypair(keypair types.String, defaultKeypair string) string {
+	if keypair.IsNull() || keypair.IsUnknown() || keypair.ValueString() == "" {
+		return defaultKeypair
+	}
+
+	return keypair.ValueString()
+}
+
+// userTags returns the tags reported by the API without the ones starting with
+// systemPrefix, the platform's own tags, unless they are also in declared.
+// All tags are returned when systemPrefix is empty.
+func userTags(ctx context.Context, declared types.List, tags []string, systemPrefix string) []string {
+	if systemPrefix == "" {
+		return tags
+	}
+
+	var current []string
+	if !declared.IsNull() && !declared.IsUnknown() {
+		declared.ElementsAs(ctx, &current, false)
+	}
+
+	result := []string{}
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, systemPrefix) || slices.Contains(current, tag) {
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
+
+// sameTags reports whether list holds the same tags as tags, in any order.
+func sameTags(ctx context.Context, list types.List, tags []string) bool {
+	if list.IsNull() || list.IsUnknown() {
+		return false
+	}
+
+	var current []string
+	if diags := list.ElementsAs(ctx, &current, false); diags.HasError() || len(current) != len(tags) {
+		return false
+	}
+
+	counts := map[string]int{}
+	for _, tag := range current {
+		counts[tag]++
+	}
+	for _, tag := range tags {
+		if counts[tag] == 0 {
+			return false
+		}
+		counts[tag]--
+	}
+
+	return true
+}
+
+// stabilizeOnReadAttempts bounds the wait in Read when stabilize_on_read is set (1 minute).
+const stabilizeOnReadAttempts = 6
+
+// maxNilClusterRetries bounds how many empty 200 responses a single poll loop tolerates.
+const maxNilClusterRetries = 3
+
+// clusterStatusError maps a cluster status to the error a wait on it ends with,
+// errClusterInProgress while it is still in progress and nil once it is ready.
+func clusterStatusError(status string) error {
+	switch status {
+	case string(sdk.CLUSTER_STATUS_IN_PROGRESS):
+		return errClusterInProgress
+	case string(sdk.CLUSTER_STATUS_ERROR):
+		return fmt.Errorf("cluster is in error state")
+	case string(sdk.CLUSTER_STATUS_DELETING):
+		return fmt.Errorf("cluster is in deleting state")
+	case string(sdk.CLUSTER_STATUS_READY):
+		return nil
+	default:
+		return fmt.Errorf("cluster is in unknown state")
+	}
+}
+
+// pollCluster runs poll under the poll semaphore until it stops returning
+// errClusterInProgress or attempts run out. retryIf, when set, names further
+// errors worth another attempt, errClusterNil is always retried a few times.
+func pollCluster(ctx context.Context, polls pollSemaphore, attempts uint, poll func(ctx context.Context) error, retryIf retry.RetryIfFunc) error {
+	return retry.Do(
+		func() error {
+			release, err := polls.acquire(ctx)
+			if err != nil {
+				return err
+			}
+			defer release()
+			return poll(ctx)
+		},
+		retry.Context(ctx),
+		retry.Delay(pollInterval),
+		retry.DelayType(retry.FixedDelay),
+		retry.Attempts(attempts),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(nilClusterRetryIf(func(err error) bool {
+			if errors.Is(err, errClusterInProgress) {
+				return true
+			}
+			return retryIf != nil && retryIf(err)
+		})),
+	)
+}
+
+// nilClusterRetryIf wraps a poll loop retry predicate so that errClusterNil is
+// retried as well, up to maxNilClusterRetries times.
+func nilClusterRetryIf(retryIf retry.RetryIfFunc) retry.RetryIfFunc {
+	nilResponses := 0
+
+	return func(err error) bool {
+		if errors.Is(err, errClusterNil) {
+			nilResponses++
+			return nilResponses <= maxNilClusterRetries
+		}
+
+		return retryIf(err)
+	}
+}
+
+// maxNotFoundClusterRetries bounds how many 404s the wait after create tolerates
+// before a new cluster could be read for the first time.
+const maxNotFoundClusterRetries = 3
+
+// defaultPollInterval is the delay between two status checks while waiting on the API.
+const defaultPollInterval = 10 * time.Second
+
+// pollInterval is the delay actually used between two status checks. It is a
+// variable so that tests can shorten it without changing the number of attempts.
+var pollInterval = defaultPollInterval
+
+// pollRequestTimeout bounds the requests of a single status check, so that a hung
+// request cannot stall a poll loop. Cancelling the operation context still aborts
+// them right away.
+const pollRequestTimeout = 2 * time.Minute
+
+// pollSemaphore caps the number of status checks in flight across all the resources
+// of a provider, as set by max_concurrent_polls. A nil pollSemaphore does not limit them.
+type pollSemaphore chan struct{}
+
+// newPollSemaphore returns a pollSemaphore allowing limit concurrent status checks,
+// or nil when limit is not positive.
+func newPollSemaphore(limit int64) pollSemaphore {
+	if limit <= 0 {
+		return nil
+	}
+
+	return make(pollSemaphore, limit)
+}
+
+// acquire waits for a free slot, or for ctx to be done, and returns the function
+// that releases the slot once the status check is over.
+func (s pollSemaphore) acquire(ctx context.Context) (func(), error) {
+	if s == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case s <- struct{}{}:
+		return func() { <-s }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// defaultBaseTimeout is how long create and resize operations are waited on.
+const defaultBaseTimeout = 10 * time.Minute
+
+// defaultLargeClusterIncrement is the extra wait granted to clusters and node
+// pools of more than largeClusterNodeCount nodes.
+const defaultLargeClusterIncrement = 10 * time.Minute
+
+// largeClusterNodeCount is the node count above which the large cluster increment applies.
+const largeClusterNodeCount = 3
+
+// calculateRetryAttempts calculates the number of status checks that fit in
+// baseTimeout, plus increment for clusters with more than largeClusterNodeCount
+// nodes. With the defaults this is 10 minutes for small clusters and 20 minutes
+// for larger ones. It always allows at least one check.
+func calculateRetryAttempts(nodeCount int64, baseTimeout, increment time.Duration) uint {
+	timeout := baseTimeout
+	if nodeCount > largeClusterNodeCount {
+		timeout += increment
+	}
+
+	return max(uint(timeout/defaultPollInterval), 1)
+}
+
+// listNodePoolsAttempts bounds how long Update waits for the default node pool to be listed (1 minute).
+const listNodePoolsAttempts = 6
+
+// transientListError reports whether a failed node pool listing is worth retrying: the
+// default node pool missing from the list, an empty body, a busy cluster or a server error.
+func transientListError(err error) bool {
+	if errors.Is(err, errDefaultNodePoolMissing) || errors.Is(err, errNodePoolsNil) {
+		return true
+	}
+
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusConflict || apiErr.StatusCode >= http.StatusInternalServerError)
+}
+
+// deletePollAttempts bounds how long deletions are waited for (10 minutes, independent of node count).
+const deletePollAttempts = 60
+
+// pollProgress counts the attempts of a poll loop, so that a loop that runs out of
+// them can tell how long it waited.
+type pollProgress struct {
+	start    time.Time
+	attempts uint
+}
+
+func newPollProgress() *pollProgress {
+	return &pollProgress{start: time.Now()}
+}
+
+// attempt records an attempt, it is called at the start of each one.
+func (p *pollProgress) attempt() {
+	p.attempts++
+}
+
+// timeoutError adds the number of attempts and the elapsed time to err when the poll
+// loop used up all of maxAttempts, as err is then only the last status observed.
+func (p *pollProgress) timeoutError(err error, maxAttempts uint) error {
+	if err == nil || p.attempts < maxAttempts {
+		return err
+	}
+
+	return fmt.Errorf("%w (gave up after %d attempts / %s)", err, p.attempts, time.Since(p.start).Round(time.Second))
+}
+
+// lookupDefaultNodePool fills in the default node pool id and its server group from
+// the node pool listing. The default node pool never changes, so it is only looked up
+// until it and its server group, assigned once its servers are scheduled, are known.
+func (r *ClusterResource) lookupDefaultNodePool(ctx context.Context, id string, data *ClusterResourceModel) error {
+	if !data.DefaultNodePoolId.IsNull() && !data.DefaultNodePoolId.IsUnknown() && data.ServerGroupId.ValueString() != "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pollRequestTimeout)
+	defer cancel()
+
+	listResult, err := r.client.ListNodePoolsWithResponse(ctx, id, &sdk.ListNodePoolsParams{
+		OnlyDefault: &[]bool{true}[0],
+	})
+	if err != nil {
+		return err
+	}
+	if listResult.StatusCode() != 200 {
+		return newStatusError(listResult.HTTPResponse, listResult.Body)
+	}
+	if listResult.JSON200 == nil {
+		return missingBodyError(listResult.HTTPResponse, listResult.Body, errNodePoolsNil)
+	}
+	// It may not exist yet while the cluster is being created
+	data.DefaultNodePoolId = types.StringNull()
+	data.ServerGroupId = types.StringNull()
+	for _, nodePool := range *listResult.JSON200 {
+		if nodePool.IsDefault {
+			data.DefaultNodePoolId = types.StringValue(nodePool.Id)
+			data.ServerGroupId = types.StringValue(nodePool.ServerGroupID)
+			break
+		}
+	}
+
+	return nil
+}
+
+// readDefaultNodePool fills in the create inputs that ShowCluster does not return
+// (network, flavor, volume size and node count) from the default node pool.
+func (r *ClusterResource) readDefaultNodePool(ctx context.Context, id string, data *ClusterResourceModel) error {
+	result, err := r.client.ListNodePoolsWithResponse(ctx, id, &sdk.ListNodePoolsParams{
+		OnlyDefault: &[]bool{true}[0],
+	})
 	if err != nil {
 		return err
 	}
 	if result.StatusCode() != 200 {
-		return fmt.Errorf("http response status code: %d", result.StatusCode())
+		return newStatusError(result.HTTPResponse, result.Body)
 	}
 	if result.JSON200 == nil {
-		return fmt.Errorf("cluster is nil")
+		return missingBodyError(result.HTTPResponse, result.Body, errNodePoolsNil)
+	}
+
+	for _, nodePool := range *result.JSON200 {
+		if nodePool.IsDefault {
+			data.DefaultNodePoolId = types.StringValue(nodePool.Id)
+			data.ServerGroupId = types.StringValue(nodePool.ServerGroupID)
+			data.NetworkId = types.StringValue(nodePool.NetworkID)
+			data.FlavorId = types.StringValue(nodePool.FlavorID)
+			data.VolumeSize = types.Int64Value(nodePool.VolumeSize)
+			data.NodeCount = types.Int64Value(nodePool.NodeCount)
+			return nil
+		}
+	}
+
+	return errDefaultNodePoolMissing
+}
+
+// showCluster fetches a cluster, failing unless ShowCluster answers 200 with a body.
+func showCluster(ctx context.Context, client *sdk.ClientWithResponses, id string) (*sdk.ShowClusterResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, pollRequestTimeout)
+	defer cancel()
+
+	params := &sdk.ShowClusterParams{}
+	result, err := client.ShowClusterWithResponse(ctx, id, params)
+	if err != nil {
+		return nil, err
+	}
+	if result.StatusCode() != 200 {
+		return nil, newStatusError(result.HTTPResponse, result.Body)
+	}
+	if result.JSON200 == nil {
+		return nil, missingBodyError(result.HTTPResponse, result.Body, errClusterNil)
+	}
+
+	return result, nil
+}
+
+func (r *ClusterResource) readCluster(ctx context.Context, id string, data *ClusterResourceModel) error {
+	result, err := showCluster(ctx, r.client, id)
+	if err != nil {
+		return err
 	}
 
 	data.Id = types.StringValue(result.JSON200.Id)
@@ -544,19 +1369,30 @@ func (r *ClusterResource) readCluster(ctx context.Context, id string, data *Clus
 	data.ProjectId = types.StringValue(result.JSON200.ProjectID)
 	data.ControlPlaneName = types.StringValue(result.JSON200.ControlPlaneName)
 	data.ControlPlaneNamespace = types.StringValue(result.JSON200.ControlPlaneNamespace)
+	// Note: the ShowCluster response does not carry the API server endpoint or CA,
+	// so api_server_url/api_server_ca cannot be exposed until the API returns them.
+	// For the same reason there is no endpoint to probe for a connection_ready check.
 	data.Keypair = types.StringValue(result.JSON200.Keypair)
 	if result.JSON200.Tags != nil {
-		listValues, diags := types.ListValueFrom(ctx, types.StringType, *result.JSON200.Tags)
-		if diags.HasError() {
-			return fmt.Errorf("failed to convert tags to list")
+		tags := userTags(ctx, data.Tags, *result.JSON200.Tags, data.SystemTagPrefix.ValueString())
+		// The API does not preserve the order of tags, keep the configured order
+		// unless the tags themselves changed
+		if !sameTags(ctx, data.Tags, tags) {
+			listValues, diags := types.ListValueFrom(ctx, types.StringType, tags)
+			if diags.HasError() {
+				return fmt.Errorf("failed to convert tags to list")
+			}
+			data.Tags = listValues
 		}
-		data.Tags = listValues
 	} else {
 		data.Tags = types.ListNull(types.StringType)
 	}
 	data.Status = types.StringValue(result.JSON200.Status)
-	data.Phase = types.StringValue(result.JSON200.Phase)
+	data.Ready = types.BoolValue(result.JSON200.Status == string(sdk.CLUSTER_STATUS_READY))
+
+	data.Phase = types.StringValue(normalizeClusterPhase(result.JSON200.Phase))
 	data.LastErrorId = types.StringValue(result.JSON200.LastErrorID)
+	// Note: the API has no endpoint resolving an error id, so there is no last_error_message
 	data.CreatedAt = types.Int64Value(result.JSON200.CreatedAt)
 	data.UpdatedAt = types.Int64Value(result.JSON200.UpdatedAt)
 	data.Deleted = types.BoolValue(result.JSON200.Deleted)
@@ -565,6 +1401,12 @@ func (r *ClusterResource) readCluster(ctx context.Context, id string, data *Clus
 	} else {
 		data.DeletedAt = types.Int64Null()
 	}
+	data.CreatedAtRFC3339 = epochToRFC3339(result.JSON200.CreatedAt)
+	data.UpdatedAtRFC3339 = epochToRFC3339(result.JSON200.UpdatedAt)
+	data.DeletedAtRFC3339 = types.StringNull()
+	if result.JSON200.DeletedAt != nil {
+		data.DeletedAtRFC3339 = epochToRFC3339(*result.JSON200.DeletedAt)
+	}
 
 	return nil
 }
@@ -0,0 +1,627 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/QumulusTechnology/strato-project/sdk"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNilClusterRetryIf(t *testing.T) {
+	inProgress := errors.New("cluster is in progress")
+	retryIf := nilClusterRetryIf(func(err error) bool {
+		return err == inProgress
+	})
+
+	for i := 1; i <= maxNilClusterRetries; i++ {
+		if !retryIf(errClusterNil) {
+			t.Fatalf("expected empty response %d to be retried", i)
+		}
+	}
+	if retryIf(errClusterNil) {
+		t.Fatalf("expected empty response %d to not be retried", maxNilClusterRetries+1)
+	}
+
+	if !retryIf(inProgress) {
+		t.Fatal("expected wrapped predicate to still retry in progress errors")
+	}
+	if retryIf(errors.New("cluster is in error state")) {
+		t.Fatal("expected wrapped predicate to not retry other errors")
+	}
+}
+
+func TestCalculateRetryAttempts(t *testing.T) {
+	cases := map[string]struct {
+		nodeCount   int64
+		baseTimeout time.Duration
+		increment   time.Duration
+		expected    uint
+	}{
+		"single node": {
+			nodeCount:   1,
+			baseTimeout: defaultBaseTimeout,
+			increment:   defaultLargeClusterIncrement,
+			expected:    60,
+		},
+		"at threshold": {
+			nodeCount:   largeClusterNodeCount,
+			baseTimeout: defaultBaseTimeout,
+			increment:   defaultLargeClusterIncrement,
+			expected:    60,
+		},
+		"above threshold": {
+			nodeCount:   largeClusterNodeCount + 1,
+			baseTimeout: defaultBaseTimeout,
+			increment:   defaultLargeClusterIncrement,
+			expected:    120,
+		},
+		"custom timeouts": {
+			nodeCount:   10,
+			baseTimeout: 5 * time.Minute,
+			increment:   time.Minute,
+			expected:    36,
+		},
+		"increment ignored for small clusters": {
+			nodeCount:   2,
+			baseTimeout: 5 * time.Minute,
+			increment:   time.Hour,
+			expected:    30,
+		},
+		"at least one attempt": {
+			nodeCount:   1,
+			baseTimeout: time.Second,
+			expected:    1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := calculateRetryAttempts(tc.nodeCount, tc.baseTimeout, tc.increment); got != tc.expected {
+				t.Errorf("expected %d attempts, got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPollSemaphore(t *testing.T) {
+	if release, err := newPollSemaphore(0).acquire(context.Background()); err != nil {
+		t.Fatalf("expected an unlimited semaphore to never block, got %s", err)
+	} else {
+		release()
+	}
+
+	polls := newPollSemaphore(1)
+	release, err := polls.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := polls.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the second acquire to wait for the context, got %v", err)
+	}
+
+	release()
+	if release, err := polls.acquire(context.Background()); err != nil {
+		t.Fatalf("expected a released slot to be available, got %s", err)
+	} else {
+		release()
+	}
+}
+
+func TestPollProgressTimeoutError(t *testing.T) {
+	inProgress := errors.New("cluster is in progress")
+
+	progress := newPollProgress()
+	progress.attempt()
+	if err := progress.timeoutError(inProgress, 2); err != inProgress {
+		t.Errorf("expected the error as is before all attempts are used, got %v", err)
+	}
+
+	progress.attempt()
+	err := progress.timeoutError(inProgress, 2)
+	if !errors.Is(err, inProgress) {
+		t.Errorf("expected the last poll error to be wrapped, got %v", err)
+	}
+	if expected := "cluster is in progress (gave up after 2 attempts / 0s)"; err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+
+	if err := progress.timeoutError(nil, 2); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestIdRegexps(t *testing.T) {
+	cases := map[string]struct {
+		value          string
+		expectNetwork  bool
+		expectFlavorId bool
+	}{
+		"uuid":          {value: "8a1c5a0e-3f0b-4c8e-9d2a-6b7e1f0c9d41", expectNetwork: true, expectFlavorId: true},
+		"custom flavor": {value: "m1.small", expectFlavorId: true},
+		"display name":  {value: "Public Network"},
+		"empty":         {value: ""},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := uuidRegexp.MatchString(tc.value); got != tc.expectNetwork {
+				t.Errorf("expected network id match %t, got %t", tc.expectNetwork, got)
+			}
+			if got := flavorIdRegexp.MatchString(tc.value); got != tc.expectFlavorId {
+				t.Errorf("expected flavor id match %t, got %t", tc.expectFlavorId, got)
+			}
+		})
+	}
+}
+
+func TestAgeSeconds(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	if got := ageSeconds(0, now); !got.IsNull() {
+		t.Errorf("expected null for an unset timestamp, got %s", got)
+	}
+	if got := ageSeconds(1700000000-90, now).ValueInt64(); got != 90 {
+		t.Errorf("expected 90, got %d", got)
+	}
+	if got := ageSeconds(1700000000+5, now).ValueInt64(); got != 0 {
+		t.Errorf("expected a timestamp in the future to count as 0, got %d", got)
+	}
+}
+
+func TestEpochToRFC3339(t *testing.T) {
+	if got := epochToRFC3339(0); !got.IsNull() {
+		t.Errorf("expected null for an unset timestamp, got %s", got)
+	}
+	if got := epochToRFC3339(1700000000).ValueString(); got != "2023-11-14T22:13:20Z" {
+		t.Errorf("expected 2023-11-14T22:13:20Z, got %s", got)
+	}
+}
+
+func TestSameTags(t *testing.T) {
+	cases := map[string]struct {
+		list     types.List
+		tags     []string
+		expected bool
+	}{
+		"same order":      {list: testTagList(t, "a", "b"), tags: []string{"a", "b"}, expected: true},
+		"different order": {list: testTagList(t, "a", "b"), tags: []string{"b", "a"}, expected: true},
+		"different tags":  {list: testTagList(t, "a", "b"), tags: []string{"a", "c"}},
+		"extra tag":       {list: testTagList(t, "a"), tags: []string{"a", "a"}},
+		"null":            {list: types.ListNull(types.StringType), tags: []string{}},
+		"unknown":         {list: types.ListUnknown(types.StringType), tags: []string{}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := sameTags(context.Background(), tc.list, tc.tags); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestUserTags(t *testing.T) {
+	cases := map[string]struct {
+		declared types.List
+		tags     []string
+		prefix   string
+		expected []string
+	}{
+		"no prefix":          {declared: testTagList(t, "a"), tags: []string{"a", "sys:b"}, expected: []string{"a", "sys:b"}},
+		"system tag dropped": {declared: testTagList(t, "a"), tags: []string{"a", "sys:b"}, prefix: "sys:", expected: []string{"a"}},
+		"system tag declared": {
+			declared: testTagList(t, "a", "sys:b"),
+			tags:     []string{"sys:b", "a", "sys:c"},
+			prefix:   "sys:",
+			expected: []string{"sys:b", "a"},
+		},
+		"null": {declared: types.ListNull(types.StringType), tags: []string{"a", "sys:b"}, prefix: "sys:", expected: []string{"a"}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := userTags(context.Background(), tc.declared, tc.tags, tc.prefix)
+			if !slices.Equal(got, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func testTagList(t *testing.T, tags ...string) types.List {
+	t.Helper()
+
+	list, diags := types.ListValueFrom(context.Background(), types.StringType, tags)
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	return list
+}
+
+func testClusterModel(nodeCount int64) ClusterResourceModel {
+	return ClusterResourceModel{
+		ClusterId:  types.StringValue("openstack-cluster"),
+		ProjectId:  types.StringValue("openstack-project"),
+		Name:       types.StringValue("test"),
+		Keypair:    types.StringValue("keypair"),
+		NetworkId:  types.StringValue("network"),
+		FlavorId:   types.StringValue("flavor"),
+		VolumeSize: types.Int64Value(20),
+		NodeCount:  types.Int64Value(nodeCount),
+		Tags:       types.ListNull(types.StringType),
+	}
+}
+
+// testCreateCluster creates a cluster on the mock server and returns its state.
+func testCreateCluster(t *testing.T, r *ClusterResource, model ClusterResourceModel) (tfsdk.State, diag.Diagnostics) {
+	t.Helper()
+
+	s := testResourceSchema(t, r)
+	plan, config := testPlan(t, s, &model)
+	resp := resource.CreateResponse{State: testState(t, s, nil)}
+	r.Create(context.Background(), resource.CreateRequest{Plan: plan, Config: config}, &resp)
+
+	return resp.State, resp.Diagnostics
+}
+
+func TestClusterResourceCreate(t *testing.T) {
+	cases := map[string]struct {
+		settleAfter     int
+		clusterStatus   sdk.ClusterStatus
+		allowErrorState bool
+		skipWait        bool
+		expectError     bool
+		expectWarning   bool
+		expectedStatus  sdk.ClusterStatus
+		failingCreates  int
+		recreateOnError bool
+		expectedCreates int
+		unpropagated    int
+		waitForPhase    clusterPhase
+	}{
+		"ready": {
+			settleAfter:    2,
+			clusterStatus:  sdk.CLUSTER_STATUS_READY,
+			expectedStatus: sdk.CLUSTER_STATUS_READY,
+		},
+		"not found right after create": {
+			settleAfter:    2,
+			clusterStatus:  sdk.CLUSTER_STATUS_READY,
+			unpropagated:   2,
+			expectedStatus: sdk.CLUSTER_STATUS_READY,
+		},
+		"error state": {
+			settleAfter:    2,
+			clusterStatus:  sdk.CLUSTER_STATUS_ERROR,
+			expectError:    true,
+			expectedStatus: sdk.CLUSTER_STATUS_ERROR,
+		},
+		"error state allowed": {
+			settleAfter:     2,
+			clusterStatus:   sdk.CLUSTER_STATUS_ERROR,
+			allowErrorState: true,
+			expectWarning:   true,
+			expectedStatus:  sdk.CLUSTER_STATUS_ERROR,
+		},
+		"recreated after error state": {
+			settleAfter:     2,
+			clusterStatus:   sdk.CLUSTER_STATUS_READY,
+			failingCreates:  1,
+			recreateOnError: true,
+			expectedStatus:  sdk.CLUSTER_STATUS_READY,
+			expectedCreates: 2,
+		},
+		"error state after recreation": {
+			settleAfter:     2,
+			clusterStatus:   sdk.CLUSTER_STATUS_READY,
+			failingCreates:  2,
+			recreateOnError: true,
+			expectError:     true,
+			expectedStatus:  sdk.CLUSTER_STATUS_ERROR,
+			expectedCreates: 2,
+		},
+		"timeout": {
+			settleAfter:    1000,
+			clusterStatus:  sdk.CLUSTER_STATUS_READY,
+			expectError:    true,
+			expectedStatus: sdk.CLUSTER_STATUS_IN_PROGRESS,
+		},
+		"phase reached": {
+			settleAfter:    1000,
+			clusterStatus:  sdk.CLUSTER_STATUS_READY,
+			waitForPhase:   CLUSTER_PHASE_PROVISIONED,
+			expectedStatus: sdk.CLUSTER_STATUS_IN_PROGRESS,
+		},
+		"without waiting": {
+			settleAfter:    2,
+			clusterStatus:  sdk.CLUSTER_STATUS_READY,
+			skipWait:       true,
+			expectedStatus: sdk.CLUSTER_STATUS_IN_PROGRESS,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := newMockStratoServer(t)
+			server.settleAfter = tc.settleAfter
+			server.clusterStatus = string(tc.clusterStatus)
+			server.failingCreates = tc.failingCreates
+			server.unpropagatedReads = tc.unpropagated
+			r := &ClusterResource{client: server.client(t)}
+
+			model := testClusterModel(1)
+			model.AllowErrorState = types.BoolValue(tc.allowErrorState)
+			model.RecreateOnError = types.BoolValue(tc.recreateOnError)
+			model.WaitForReady = types.BoolValue(!tc.skipWait)
+			if tc.waitForPhase != "" {
+				model.WaitForPhase = types.StringValue(string(tc.waitForPhase))
+			}
+			state, diags := testCreateCluster(t, r, model)
+
+			if diags.HasError() != tc.expectError {
+				t.Fatalf("expected error %t, got diagnostics: %v", tc.expectError, diags)
+			}
+			if (diags.WarningsCount() > 0) != tc.expectWarning {
+				t.Fatalf("expected warning %t, got diagnostics: %v", tc.expectWarning, diags)
+			}
+
+			// The cluster must be tracked in state even when it never became ready
+			var data ClusterResourceModel
+			if diags := state.Get(context.Background(), &data); diags.HasError() {
+				t.Fatalf("unexpected state diagnostics: %v", diags)
+			}
+			if data.Id.ValueString() == "" {
+				t.Error("expected cluster id to be saved in state")
+			}
+			// The default node pool is only looked up once the wait succeeded
+			if !tc.expectError && !tc.expectWarning {
+				if data.DefaultNodePoolId.ValueString() == "" {
+					t.Error("expected default node pool id to be saved in state")
+				}
+				if data.ServerGroupId.ValueString() != "server-group-"+data.DefaultNodePoolId.ValueString() {
+					t.Errorf("expected the default node pool server group id, got %q", data.ServerGroupId.ValueString())
+				}
+				if lists := server.nodePoolListCount(); lists != 1 {
+					t.Errorf("expected the node pools to be listed once, got %d listings", lists)
+				}
+			}
+			if data.Status.ValueString() != string(tc.expectedStatus) {
+				t.Errorf("expected status %q, got %q", tc.expectedStatus, data.Status.ValueString())
+			}
+			if tc.expectedCreates > 0 {
+				if creates := server.requestCount(http.MethodPost); creates != tc.expectedCreates {
+					t.Errorf("expected %d create requests, got %d", tc.expectedCreates, creates)
+				}
+			}
+		})
+	}
+}
+
+func TestClusterResourceModifyPlan(t *testing.T) {
+	cases := map[string]struct {
+		nodeCount   int64
+		existing    bool
+		expectError bool
+	}{
+		"create":              {nodeCount: 1},
+		"create with no node": {nodeCount: 0, expectError: true},
+		"scale to zero":       {nodeCount: 0, existing: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &ClusterResource{}
+			s := testResourceSchema(t, r)
+
+			model := testClusterModel(tc.nodeCount)
+			plan, config := testPlan(t, s, &model)
+			state := testState(t, s, nil)
+			if tc.existing {
+				prior := testClusterModel(1)
+				state = testState(t, s, &prior)
+			}
+			resp := resource.ModifyPlanResponse{Plan: plan}
+			r.ModifyPlan(context.Background(), resource.ModifyPlanRequest{Plan: plan, Config: config, State: state}, &resp)
+
+			if resp.Diagnostics.HasError() != tc.expectError {
+				t.Errorf("expected error %t, got diagnostics: %v", tc.expectError, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestClusterResourceCreateTimeout(t *testing.T) {
+	server := newMockStratoServer(t)
+	server.settleAfter = 1000
+	r := &ClusterResource{client: server.client(t)}
+
+	_, diags := testCreateCluster(t, r, testClusterModel(1))
+
+	if diags.ErrorsCount() != 1 {
+		t.Fatalf("expected 1 error, got diagnostics: %v", diags)
+	}
+	if summary := diags.Errors()[0].Summary(); summary != "Unable to create cluster" {
+		t.Errorf("expected summary %q, got %q", "Unable to create cluster", summary)
+	}
+	// Only the last status is reported, along with how long the wait lasted
+	attempts := calculateRetryAttempts(1, defaultBaseTimeout, defaultLargeClusterIncrement)
+	expected := regexp.MustCompile(fmt.Sprintf(`^cluster is in progress \(gave up after %d attempts / \d+s\)$`, attempts))
+	if detail := diags.Errors()[0].Detail(); !expected.MatchString(detail) {
+		t.Errorf("expected detail to match %s, got %q", expected, detail)
+	}
+}
+
+func TestClusterResourceUpdate(t *testing.T) {
+	cases := map[string]struct {
+		nodeCount          int64
+		expectedPuts       int
+		nodePoolStatus     sdk.NodePoolStatus
+		emptyNodePoolLists int
+		conflictingUpdates int
+		settleAfter        int
+		waitForPhase       clusterPhase
+		expectError        bool
+	}{
+		"unchanged node count": {
+			nodeCount:      1,
+			nodePoolStatus: sdk.NODE_POOL_STATUS_READY,
+		},
+		"resize": {
+			nodeCount:      3,
+			expectedPuts:   1,
+			nodePoolStatus: sdk.NODE_POOL_STATUS_READY,
+		},
+		"scale to zero": {
+			nodeCount:      0,
+			expectedPuts:   1,
+			nodePoolStatus: sdk.NODE_POOL_STATUS_READY,
+		},
+		"resize error": {
+			nodeCount:      3,
+			expectedPuts:   1,
+			nodePoolStatus: sdk.NODE_POOL_STATUS_ERROR,
+			expectError:    true,
+		},
+		"cluster busy": {
+			nodeCount:          3,
+			expectedPuts:       3,
+			nodePoolStatus:     sdk.NODE_POOL_STATUS_READY,
+			conflictingUpdates: 2,
+		},
+		"default node pool briefly missing": {
+			nodeCount:          3,
+			expectedPuts:       1,
+			nodePoolStatus:     sdk.NODE_POOL_STATUS_READY,
+			emptyNodePoolLists: 2,
+		},
+		"default node pool missing": {
+			nodeCount:          3,
+			nodePoolStatus:     sdk.NODE_POOL_STATUS_READY,
+			emptyNodePoolLists: listNodePoolsAttempts,
+			expectError:        true,
+		},
+		"phase reached": {
+			nodeCount:      3,
+			expectedPuts:   1,
+			nodePoolStatus: sdk.NODE_POOL_STATUS_READY,
+			settleAfter:    1000,
+			waitForPhase:   CLUSTER_PHASE_PROVISIONED,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := newMockStratoServer(t)
+			if tc.settleAfter > 0 {
+				server.settleAfter = tc.settleAfter
+			}
+			r := &ClusterResource{client: server.client(t)}
+			s := testResourceSchema(t, r)
+
+			model := testClusterModel(1)
+			if tc.waitForPhase != "" {
+				model.WaitForPhase = types.StringValue(string(tc.waitForPhase))
+			}
+			state, diags := testCreateCluster(t, r, model)
+			if diags.HasError() {
+				t.Fatalf("unexpected create diagnostics: %v", diags)
+			}
+			var data ClusterResourceModel
+			state.Get(context.Background(), &data)
+
+			server.nodePoolStatus = string(tc.nodePoolStatus)
+			server.emptyNodePoolLists = tc.emptyNodePoolLists
+			server.conflictingUpdates = tc.conflictingUpdates
+			data.NodeCount = types.Int64Value(tc.nodeCount)
+			plan, config := testPlan(t, s, &data)
+			resp := resource.UpdateResponse{State: state}
+			r.Update(context.Background(), resource.UpdateRequest{Plan: plan, Config: config, State: state}, &resp)
+
+			if resp.Diagnostics.HasError() != tc.expectError {
+				t.Fatalf("expected error %t, got diagnostics: %v", tc.expectError, resp.Diagnostics)
+			}
+			if puts := server.requestCount(http.MethodPut); puts != tc.expectedPuts {
+				t.Errorf("expected %d update requests, got %d", tc.expectedPuts, puts)
+			}
+			if tc.expectError {
+				return
+			}
+
+			resp.State.Get(context.Background(), &data)
+			if data.NodeCount.ValueInt64() != tc.nodeCount {
+				t.Errorf("expected node count %d, got %d", tc.nodeCount, data.NodeCount.ValueInt64())
+			}
+		})
+	}
+}
+
+func TestClusterResourceDelete(t *testing.T) {
+	cases := map[string]struct {
+		forceDelete        bool
+		ignoreDeletes      bool
+		deletionProtection bool
+		expectError        bool
+		expectDeleted      bool
+	}{
+		"wait for deletion": {
+			expectDeleted: true,
+		},
+		"force delete": {
+			forceDelete: true,
+		},
+		"delete ignored": {
+			ignoreDeletes: true,
+			expectError:   true,
+		},
+		"deletion protection": {
+			deletionProtection: true,
+			expectError:        true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := newMockStratoServer(t)
+			r := &ClusterResource{client: server.client(t)}
+
+			model := testClusterModel(1)
+			model.ForceDelete = types.BoolValue(tc.forceDelete)
+			model.DeletionProtection = types.BoolValue(tc.deletionProtection)
+			state, diags := testCreateCluster(t, r, model)
+			if diags.HasError() {
+				t.Fatalf("unexpected create diagnostics: %v", diags)
+			}
+
+			server.ignoreDeletes = tc.ignoreDeletes
+			resp := resource.DeleteResponse{State: state}
+			r.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+
+			if resp.Diagnostics.HasError() != tc.expectError {
+				t.Fatalf("expected error %t, got diagnostics: %v", tc.expectError, resp.Diagnostics)
+			}
+			if deleted := len(server.clusters) == 0; deleted != tc.expectDeleted {
+				t.Errorf("expected cluster deleted %t, got %t", tc.expectDeleted, deleted)
+			}
+			if tc.deletionProtection && server.requestCount(http.MethodDelete) != 0 {
+				t.Error("expected no delete request for a protected cluster")
+			}
+		})
+	}
+}
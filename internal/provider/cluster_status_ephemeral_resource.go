@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/QumulusTechnology/strato-project/sdk"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &ClusterStatusEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &ClusterStatusEphemeralResource{}
+
+func NewClusterStatusEphemeralResource() ephemeral.EphemeralResource {
+	return &ClusterStatusEphemeralResource{}
+}
+
+// ClusterStatusEphemeralResource defines the ephemeral resource implementation.
+type ClusterStatusEphemeralResource struct {
+	client *sdk.ClientWithResponses
+	polls  pollSemaphore
+}
+
+// ClusterStatusEphemeralResourceModel describes the ephemeral resource data model.
+type ClusterStatusEphemeralResourceModel struct {
+	ClusterId      types.String `tfsdk:"cluster_id"`
+	TimeoutSeconds types.Int64  `tfsdk:"timeout_seconds"`
+	Status         types.String `tfsdk:"status"`
+	Phase          types.String `tfsdk:"phase"`
+	Ready          types.Bool   `tfsdk:"ready"`
+	LastErrorId    types.String `tfsdk:"last_error_id"`
+}
+
+// defaultClusterStatusTimeout is how long the cluster status is waited on when timeout_seconds is unset.
+const defaultClusterStatusTimeout = 10 * time.Minute
+
+func (r *ClusterStatusEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_status"
+}
+
+func (r *ClusterStatusEphemeralResource) Schema(ctx context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Waits for an existing cluster to settle, e.g. to gate other operations on it being ready, without tracking it in state",
+
+		Attributes: map[string]schema.Attribute{
+			"cluster_id": schema.StringAttribute{
+				MarkdownDescription: "Cluster identifier",
+				Required:            true,
+			},
+			"timeout_seconds": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("How long to wait for a cluster that is in progress to settle. Defaults to %d", int64(defaultClusterStatusTimeout/time.Second)),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Cluster status",
+				Computed:            true,
+			},
+			"phase": schema.StringAttribute{
+				MarkdownDescription: clusterPhaseDescription,
+				Computed:            true,
+			},
+			"ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether the cluster status is ready",
+				Computed:            true,
+			},
+			"last_error_id": schema.StringAttribute{
+				MarkdownDescription: "Cluster last error id",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *ClusterStatusEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*stratoResourceData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *stratoResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = data.client
+	r.polls = data.polls
+}
+
+func (r *ClusterStatusEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ClusterStatusEphemeralResourceModel
+
+	// Read Terraform config data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout := defaultClusterStatusTimeout
+	if !data.TimeoutSeconds.IsNull() {
+		timeout = time.Duration(data.TimeoutSeconds.ValueInt64()) * time.Second
+	}
+
+	statuses := newStatusLogger("Cluster", data.ClusterId.ValueString())
+	err := pollCluster(ctx, r.polls, calculateRetryAttempts(0, timeout, 0),
+		func(ctx context.Context) error {
+			result, err := showCluster(ctx, r.client, data.ClusterId.ValueString())
+			if err != nil {
+				return err
+			}
+			statuses.observe(ctx, result.JSON200.Status)
+			data.Status = types.StringValue(result.JSON200.Status)
+			data.Phase = types.StringValue(normalizeClusterPhase(result.JSON200.Phase))
+			data.Ready = types.BoolValue(result.JSON200.Status == string(sdk.CLUSTER_STATUS_READY))
+			data.LastErrorId = types.StringValue(result.JSON200.LastErrorID)
+			// Any settled status is reported as is, only a cluster in progress is waited on
+			if err := clusterStatusError(result.JSON200.Status); errors.Is(err, errClusterInProgress) {
+				return err
+			}
+			return nil
+		},
+		nil,
+	)
+
+	// A cluster still in progress after the wait is reported as not ready
+	if err != nil && !errors.Is(err, errClusterInProgress) {
+		addAPIError(&resp.Diagnostics, "Unable to read cluster status", err)
+		return
+	}
+	warnUnknownClusterPhase(ctx, data.Phase.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddWarning(
+			"Cluster is still in progress",
+			fmt.Sprintf("Cluster %s did not settle within %s.", data.ClusterId.ValueString(), timeout),
+		)
+	}
+
+	// Save data into ephemeral result data
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
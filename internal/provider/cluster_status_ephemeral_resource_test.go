@@ -0,0 +1,116 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"github.com/QumulusTechnology/strato-project/sdk"
+)
+
+func TestClusterStatusEphemeralResourceOpen(t *testing.T) {
+	cases := map[string]struct {
+		clusterId      string
+		status         string
+		settleAfter    int
+		clusterStatus  string
+		timeoutSeconds types.Int64
+		expectError    bool
+		expectWarning  bool
+		expectStatus   string
+	}{
+		"ready": {
+			status:       string(sdk.CLUSTER_STATUS_READY),
+			expectStatus: string(sdk.CLUSTER_STATUS_READY),
+		},
+		"settles within the timeout": {
+			status:       string(sdk.CLUSTER_STATUS_IN_PROGRESS),
+			settleAfter:  2,
+			expectStatus: string(sdk.CLUSTER_STATUS_READY),
+		},
+		// A zero timeout allows a single read
+		"in progress until the timeout": {
+			status:         string(sdk.CLUSTER_STATUS_IN_PROGRESS),
+			settleAfter:    1000,
+			timeoutSeconds: types.Int64Value(0),
+			expectWarning:  true,
+			expectStatus:   string(sdk.CLUSTER_STATUS_IN_PROGRESS),
+		},
+		"error state": {
+			status:        string(sdk.CLUSTER_STATUS_IN_PROGRESS),
+			settleAfter:   2,
+			clusterStatus: string(sdk.CLUSTER_STATUS_ERROR),
+			expectStatus:  string(sdk.CLUSTER_STATUS_ERROR),
+		},
+		"not found": {
+			clusterId:   "cluster-unknown",
+			expectError: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := newMockStratoServer(t)
+			server.settleAfter = tc.settleAfter
+			if tc.clusterStatus != "" {
+				server.clusterStatus = tc.clusterStatus
+			}
+			server.addCluster("cluster-1")
+			server.clusters["cluster-1"].status = tc.status
+			r := &ClusterStatusEphemeralResource{client: server.client(t)}
+
+			clusterId := "cluster-1"
+			if tc.clusterId != "" {
+				clusterId = tc.clusterId
+			}
+			var schemaResp ephemeral.SchemaResponse
+			r.Schema(context.Background(), ephemeral.SchemaRequest{}, &schemaResp)
+			result := tfsdk.EphemeralResultData{
+				Schema: schemaResp.Schema,
+				Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(context.Background()), nil),
+			}
+			if diags := result.Set(context.Background(), ClusterStatusEphemeralResourceModel{
+				ClusterId:      types.StringValue(clusterId),
+				TimeoutSeconds: tc.timeoutSeconds,
+				Status:         types.StringNull(),
+				Phase:          types.StringNull(),
+				Ready:          types.BoolNull(),
+				LastErrorId:    types.StringNull(),
+			}); diags.HasError() {
+				t.Fatalf("unexpected config diagnostics: %v", diags)
+			}
+
+			config := tfsdk.Config{Schema: schemaResp.Schema, Raw: result.Raw}
+			resp := ephemeral.OpenResponse{Result: result}
+			r.Open(context.Background(), ephemeral.OpenRequest{Config: config}, &resp)
+
+			if resp.Diagnostics.HasError() != tc.expectError {
+				t.Fatalf("expected error %t, got diagnostics: %v", tc.expectError, resp.Diagnostics)
+			}
+			if hasWarning := resp.Diagnostics.WarningsCount() > 0; hasWarning != tc.expectWarning {
+				t.Errorf("expected warning %t, got diagnostics: %v", tc.expectWarning, resp.Diagnostics)
+			}
+			if tc.expectError {
+				return
+			}
+
+			var data ClusterStatusEphemeralResourceModel
+			if diags := resp.Result.Get(context.Background(), &data); diags.HasError() {
+				t.Fatalf("unexpected result diagnostics: %v", diags)
+			}
+			if data.Status.ValueString() != tc.expectStatus {
+				t.Errorf("expected status %q, got %q", tc.expectStatus, data.Status.ValueString())
+			}
+			if ready := tc.expectStatus == string(sdk.CLUSTER_STATUS_READY); data.Ready.ValueBool() != ready {
+				t.Errorf("expected ready %t, got %t", ready, data.Ready.ValueBool())
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// errClusterNil is returned when ShowCluster answers 200 without a body. A cluster
+// that is really gone is reported as 404, so poll loops treat this as transient.
+var errClusterNil = errors.New("cluster is nil")
+
+// errClusterInProgress is returned by cluster poll loops while the cluster is still
+// in progress, it is the only status they keep polling on.
+var errClusterInProgress = errors.New("cluster is in progress")
+
+// errNodePoolNil is returned when a node pool endpoint answers without a body.
+var errNodePoolNil = errors.New("node pool is nil")
+
+// errNodePoolsNil is returned when ListNodePools answers without a body.
+var errNodePoolsNil = errors.New("node pools is nil")
+
+// errDefaultNodePoolMissing is returned when a cluster's default node pool is not listed.
+var errDefaultNodePoolMissing = errors.New("no default node pool found")
+
+// errClusterBusy is returned when the API answers 409 because the cluster is busy
+// with another operation, such as the creation of another node pool.
+var errClusterBusy = errors.New("cluster is busy")
+
+// isClusterBusy reports whether a response is a 409 for a cluster busy with another
+// operation, which goes away by waiting. A project out of quota is not going to be
+// fixed by waiting, so a quota conflict is not one.
+func isClusterBusy(statusCode int, body []byte) bool {
+	return statusCode == http.StatusConflict && newQuotaError(newAPIError(statusCode, body)) == nil
+}
+
+// maxErrorBodyLength bounds how much of an undecodable response body ends up in an error.
+const maxErrorBodyLength = 500
+
+// apiError is an unexpected response from the Strato API, with the error message
+// decoded from the response body when there is one. It always reads as
+// "<METHOD> <path>: http response status code: <code>[: <message>]", the request
+// part being left out only when the response is not tied to a request.
+type apiError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+	// RequestID is the OpenStack request id of the response, empty when the API did
+	// not return one. It is not part of the message, addAPIError reports it separately.
+	RequestID string
+}
+
+// openstackRequestIDHeaders are the response headers OpenStack services return their
+// request id in, by order of preference.
+var openstackRequestIDHeaders = []string{"X-Openstack-Request-Id", "X-Compute-Request-Id"}
+
+// openstackRequestID returns the id support needs to find the request in the OpenStack logs.
+func (e *apiError) openstackRequestID() string {
+	return e.RequestID
+}
+
+func (e *apiError) Error() string {
+	msg := fmt.Sprintf("%shttp response status code: %d", requestPrefix(e.Method, e.Path), e.StatusCode)
+	if e.Message == "" {
+		return msg
+	}
+	return msg + ": " + e.Message
+}
+
+// requestPrefix returns the "<METHOD> <path>: " prefix of API errors, or nothing
+// when the request is unknown.
+func requestPrefix(method, path string) string {
+	if method == "" {
+		return ""
+	}
+
+	return method + " " + path + ": "
+}
+
+// responseRequest returns the method and path of the request resp answers.
+func responseRequest(resp *http.Response) (string, string) {
+	if resp == nil || resp.Request == nil || resp.Request.URL == nil {
+		return "", ""
+	}
+
+	return resp.Request.Method, resp.Request.URL.Path
+}
+
+// statusCode returns the status code of resp, or 0 when there is no response.
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+
+	return resp.StatusCode
+}
+
+// isNotFound reports whether err is a 404 response from the API.
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// newResponseAPIError is newAPIError for the response of an SDK call, recording
+// the request it answers.
+func newResponseAPIError(resp *http.Response, body []byte) *apiError {
+	err := newAPIError(statusCode(resp), body)
+	err.Method, err.Path = responseRequest(resp)
+	if resp != nil {
+		for _, header := range openstackRequestIDHeaders {
+			if id := resp.Header.Get(header); id != "" {
+				err.RequestID = id
+				break
+			}
+		}
+	}
+
+	return err
+}
+
+// newAPIError builds an apiError from a response status code and body. JSON error
+// payloads are decoded from their usual message fields, any other body is kept verbatim.
+func newAPIError(statusCode int, body []byte) *apiError {
+	body = bytes.TrimSpace(body)
+
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err == nil {
+		for _, key := range []string{"message", "error", "detail", "msg"} {
+			if message, ok := payload[key].(string); ok && message != "" {
+				return &apiError{StatusCode: statusCode, Message: message}
+			}
+		}
+	}
+
+	message := string(body)
+	if len(message) > maxErrorBodyLength {
+		message = message[:maxErrorBodyLength] + "... [truncated]"
+	}
+
+	return &apiError{StatusCode: statusCode, Message: message}
+}
+
+// authError is a 401 or 403 response. It is reported separately from other API
+// errors so that rejected credentials are not mistaken for a transient failure.
+type authError struct {
+	*apiError
+}
+
+// hint tells the user what to check for the rejected request.
+func (e *authError) hint() string {
+	if e.StatusCode == http.StatusForbidden {
+		return "The token was accepted but is not allowed to perform this operation, check that the credentials configured on the provider belong to a user with access to the project."
+	}
+
+	return "The token was rejected, check that bearer_token (or the token printed by token_command, or the application credential) is valid and has not expired."
+}
+
+// quotaError is a response reporting that the project ran out of an OpenStack quota.
+// Nova answers 403 when it does, so it is told apart from authError by its message.
+type quotaError struct {
+	*apiError
+	// Quota is the exhausted quota (e.g. "cores", "ram" or "gigabytes"), empty when
+	// the message does not name it.
+	Quota string
+}
+
+// quotaRegexps extract the exhausted quota from the messages of Nova ("Quota exceeded
+// for cores: Requested 8, but already used 40 of 40 cores"), Neutron ("Quota exceeded
+// for resources: ['port']") and Cinder ("Requested volume or snapshot exceeds allowed
+// gigabytes quota", "Maximum number of volumes allowed (10) exceeded for quota 'volumes'").
+var quotaRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)quota exceeded for (?:resources: )?\[?'?([a-z_]+(?:'?, '?[a-z_]+)*)`),
+	regexp.MustCompile(`(?i)exceeds allowed ([a-z_]+) quota`),
+	regexp.MustCompile(`(?i)exceeded for quota '([a-z_]+)'`),
+}
+
+// newQuotaError returns a quotaError when the message of err reports an exceeded quota.
+func newQuotaError(err *apiError) *quotaError {
+	for _, re := range quotaRegexps {
+		if match := re.FindStringSubmatch(err.Message); match != nil {
+			return &quotaError{apiError: err, Quota: strings.ReplaceAll(match[1], "'", "")}
+		}
+	}
+	if strings.Contains(strings.ToLower(err.Message), "quota") {
+		return &quotaError{apiError: err}
+	}
+
+	return nil
+}
+
+// hint tells the user which quota to raise.
+func (e *quotaError) hint() string {
+	if e.Quota == "" {
+		return "The OpenStack project is out of quota. Free up resources or request a higher quota for the project, retrying will not help until then."
+	}
+
+	return fmt.Sprintf("The OpenStack project is out of %s quota. Free up resources or request a higher %s quota for the project, retrying will not help until then.", e.Quota, e.Quota)
+}
+
+// newStatusError builds the error for a response with an unexpected status code.
+func newStatusError(resp *http.Response, body []byte) error {
+	err := newResponseAPIError(resp, body)
+	if err.StatusCode >= http.StatusBadRequest && err.StatusCode < http.StatusInternalServerError {
+		if quotaErr := newQuotaError(err); quotaErr != nil {
+			return quotaErr
+		}
+	}
+	if err.StatusCode == http.StatusUnauthorized || err.StatusCode == http.StatusForbidden {
+		return &authError{err}
+	}
+
+	return err
+}
+
+// addAPIError adds err to diags under summary, or under an "Authentication failed"
+// summary with a hint on what to check when the API rejected the credentials, or a
+// "Quota exceeded" summary naming the quota to raise when the project ran out of it.
+// The OpenStack request id of the failed response, if any, is added to the detail.
+func addAPIError(diags *diag.Diagnostics, summary string, err error) {
+	var requestID string
+	var withRequestID interface{ openstackRequestID() string }
+	if errors.As(err, &withRequestID) && withRequestID.openstackRequestID() != "" {
+		requestID = "\n\nOpenStack request id: " + withRequestID.openstackRequestID()
+	}
+
+	var quotaErr *quotaError
+	if errors.As(err, &quotaErr) {
+		diags.AddError("Quota exceeded", fmt.Sprintf("%s: %s\n\n%s%s", summary, err, quotaErr.hint(), requestID))
+		return
+	}
+
+	var authErr *authError
+	if errors.As(err, &authErr) {
+		diags.AddError("Authentication failed", fmt.Sprintf("%s: %s\n\n%s%s", summary, err, authErr.hint(), requestID))
+		return
+	}
+
+	diags.AddError(summary, err.Error()+requestID)
+}
+
+// missingBodyError explains why a response could not be decoded into the expected
+// type: the decoded error payload if the body has content, otherwise nilErr (wrapped
+// with the request and status code when known).
+func missingBodyError(resp *http.Response, body []byte, nilErr error) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		method, path := responseRequest(resp)
+		if method == "" {
+			return nilErr
+		}
+		return fmt.Errorf("%shttp response status code: %d: %w", requestPrefix(method, path), statusCode(resp), nilErr)
+	}
+
+	return newResponseAPIError(resp, body)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestMissingBodyError(t *testing.T) {
+	cases := map[string]struct {
+		statusCode int
+		body       string
+		expected   string
+	}{
+		"empty body": {
+			statusCode: 200,
+			body:       " \n",
+			expected:   "GET /clusters/1: http response status code: 200: " + errClusterNil.Error(),
+		},
+		"json message": {
+			statusCode: 200,
+			body:       `{"code": 42, "message": "cluster name already in use"}`,
+			expected:   "GET /clusters/1: http response status code: 200: cluster name already in use",
+		},
+		"json error": {
+			statusCode: 400,
+			body:       `{"error": "invalid flavor"}`,
+			expected:   "GET /clusters/1: http response status code: 400: invalid flavor",
+		},
+		"plain text": {
+			statusCode: 502,
+			body:       "Bad Gateway",
+			expected:   "GET /clusters/1: http response status code: 502: Bad Gateway",
+		},
+		"truncated": {
+			statusCode: 500,
+			body:       strings.Repeat("x", maxErrorBodyLength+1),
+			expected:   "GET /clusters/1: http response status code: 500: " + strings.Repeat("x", maxErrorBodyLength) + "... [truncated]",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := missingBodyError(testResponse(tc.statusCode), []byte(tc.body), errClusterNil)
+			if err.Error() != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, err.Error())
+			}
+
+			var apiErr *apiError
+			if errors.As(err, &apiErr) == errors.Is(err, errClusterNil) {
+				t.Errorf("expected exactly one of apiError or errClusterNil, got %T", err)
+			}
+		})
+	}
+
+	if err := missingBodyError(nil, nil, errClusterNil); err != errClusterNil {
+		t.Errorf("expected errClusterNil without a response, got %v", err)
+	}
+}
+
+// testResponse returns a response with statusCode to GET /clusters/1.
+func testResponse(statusCode int) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Request:    &http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/clusters/1"}},
+	}
+}
+
+func TestNewQuotaError(t *testing.T) {
+	cases := map[string]struct {
+		message       string
+		expectQuota   bool
+		expectedQuota string
+	}{
+		"nova":           {message: "Quota exceeded for cores: Requested 8, but already used 40 of 40 cores", expectQuota: true, expectedQuota: "cores"},
+		"nova multiple":  {message: "Quota exceeded for cores, ram: Requested 8, 16384", expectQuota: true, expectedQuota: "cores, ram"},
+		"neutron":        {message: "Quota exceeded for resources: ['port'].", expectQuota: true, expectedQuota: "port"},
+		"cinder size":    {message: "Requested volume or snapshot exceeds allowed gigabytes quota. Requested 20G, quota is 1000G and 990G has been consumed.", expectQuota: true, expectedQuota: "gigabytes"},
+		"cinder count":   {message: "Maximum number of volumes allowed (10) exceeded for quota 'volumes'.", expectQuota: true, expectedQuota: "volumes"},
+		"unnamed quota":  {message: "project quota reached", expectQuota: true},
+		"not a quota":    {message: "token expired"},
+		"empty response": {},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			quotaErr := newQuotaError(&apiError{StatusCode: 403, Message: tc.message})
+			if (quotaErr != nil) != tc.expectQuota {
+				t.Fatalf("expected quota error %t, got %v", tc.expectQuota, quotaErr)
+			}
+			if quotaErr != nil && quotaErr.Quota != tc.expectedQuota {
+				t.Errorf("expected quota %q, got %q", tc.expectedQuota, quotaErr.Quota)
+			}
+		})
+	}
+}
+
+func TestAddAPIError(t *testing.T) {
+	withRequestID := func(resp *http.Response, header string) *http.Response {
+		resp.Header = http.Header{}
+		resp.Header.Set(header, "req-0b8c0f1e")
+		return resp
+	}
+
+	cases := map[string]struct {
+		err               error
+		expectedSummary   string
+		expectedDetail    string
+		expectedRequestID bool
+	}{
+		"server error": {
+			err:             newStatusError(testResponse(500), nil),
+			expectedSummary: "Unable to read cluster",
+			expectedDetail:  "GET /clusters/1: http response status code: 500",
+		},
+		"unauthorized": {
+			err:             newStatusError(testResponse(401), []byte(`{"message": "token expired"}`)),
+			expectedSummary: "Authentication failed",
+			expectedDetail:  "Unable to read cluster: GET /clusters/1: http response status code: 401: token expired",
+		},
+		"nova quota exceeded": {
+			err:             newStatusError(testResponse(403), []byte(`{"message": "Quota exceeded for cores: Requested 8, but already used 40 of 40 cores"}`)),
+			expectedSummary: "Quota exceeded",
+			expectedDetail:  "Unable to read cluster: GET /clusters/1: http response status code: 403: Quota exceeded for cores",
+		},
+		"openstack request id": {
+			err:               newStatusError(withRequestID(testResponse(500), "X-Openstack-Request-Id"), nil),
+			expectedSummary:   "Unable to read cluster",
+			expectedDetail:    "GET /clusters/1: http response status code: 500",
+			expectedRequestID: true,
+		},
+		"compute request id with quota": {
+			err:               newStatusError(withRequestID(testResponse(403), "X-Compute-Request-Id"), []byte(`{"message": "Quota exceeded for cores: Requested 8, but already used 40 of 40 cores"}`)),
+			expectedSummary:   "Quota exceeded",
+			expectedDetail:    "Unable to read cluster: GET /clusters/1: http response status code: 403: Quota exceeded for cores",
+			expectedRequestID: true,
+		},
+		"forbidden in poll loop": {
+			err:             retry.Error{newStatusError(testResponse(403), nil)},
+			expectedSummary: "Authentication failed",
+			expectedDetail:  "Unable to read cluster: All attempts fail:\n#1: GET /clusters/1: http response status code: 403",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			addAPIError(&diags, "Unable to read cluster", tc.err)
+
+			if len(diags) != 1 {
+				t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+			}
+			if diags[0].Summary() != tc.expectedSummary {
+				t.Errorf("expected summary %q, got %q", tc.expectedSummary, diags[0].Summary())
+			}
+			if !strings.HasPrefix(diags[0].Detail(), tc.expectedDetail) {
+				t.Errorf("expected detail to start with %q, got %q", tc.expectedDetail, diags[0].Detail())
+			}
+			if got := strings.HasSuffix(diags[0].Detail(), "OpenStack request id: req-0b8c0f1e"); got != tc.expectedRequestID {
+				t.Errorf("expected request id in detail %t, got %q", tc.expectedRequestID, diags[0].Detail())
+			}
+		})
+	}
+}
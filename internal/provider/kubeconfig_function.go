@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	_ function.Function = KubeconfigFunction{}
+)
+
+var kubeconfigAttributeTypes = map[string]attr.Type{
+	"host":                   types.StringType,
+	"cluster_ca_certificate": types.StringType,
+	"token":                  types.StringType,
+	"client_certificate":     types.StringType,
+	"client_key":             types.StringType,
+	"auth_mode":              types.StringType,
+}
+
+// Kubeconfig authentication modes reported by parse_kubeconfig.
+const (
+	kubeconfigAuthToken      = "token"
+	kubeconfigAuthClientCert = "client_cert"
+)
+
+func NewKubeconfigFunction() function.Function {
+	return KubeconfigFunction{}
+}
+
+// KubeconfigFunction extracts connection details from a kubeconfig document.
+type KubeconfigFunction struct{}
+
+// kubeconfig is the subset of the kubeconfig file format needed to connect to a cluster.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string `yaml:"token"`
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKeyData         string `yaml:"client-key-data"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+func (r KubeconfigFunction) Metadata(_ context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_kubeconfig"
+}
+
+func (r KubeconfigFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Parse a kubeconfig",
+		MarkdownDescription: "Extracts the API server `host`, the PEM encoded `cluster_ca_certificate` and the credentials of the current context from a kubeconfig YAML document, ready to be passed to the `kubernetes` provider. `auth_mode` tells how the user authenticates: `token` with the bearer `token`, or `client_cert` with the PEM encoded `client_certificate` and `client_key`. Credentials that are not used are null",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "kubeconfig",
+				MarkdownDescription: "Kubeconfig YAML document",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: kubeconfigAttributeTypes,
+		},
+	}
+}
+
+func (r KubeconfigFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var data string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &data))
+
+	if resp.Error != nil {
+		return
+	}
+
+	var config kubeconfig
+	if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Unable to parse kubeconfig: %s", err))
+		return
+	}
+
+	// Resolve the cluster and user from the current context, falling back to the
+	// first entries for single-cluster kubeconfigs without a current context.
+	var clusterName, userName string
+	if config.CurrentContext != "" {
+		found := false
+		for _, c := range config.Contexts {
+			if c.Name == config.CurrentContext {
+				clusterName = c.Context.Cluster
+				userName = c.Context.User
+				found = true
+				break
+			}
+		}
+		if !found {
+			resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Unable to find current context %q in kubeconfig", config.CurrentContext))
+			return
+		}
+	}
+
+	host := types.StringNull()
+	caCertificate := types.StringNull()
+	for _, c := range config.Clusters {
+		if c.Name == clusterName || clusterName == "" {
+			host = types.StringValue(c.Cluster.Server)
+			if c.Cluster.CertificateAuthorityData != "" {
+				pem, err := base64.StdEncoding.DecodeString(c.Cluster.CertificateAuthorityData)
+				if err != nil {
+					resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Unable to decode certificate-authority-data: %s", err))
+					return
+				}
+				caCertificate = types.StringValue(string(pem))
+			}
+			break
+		}
+	}
+	if host.IsNull() {
+		resp.Error = function.NewArgumentFuncError(0, "Unable to find a cluster in kubeconfig")
+		return
+	}
+
+	token := types.StringNull()
+	clientCertificate := types.StringNull()
+	clientKey := types.StringNull()
+	authMode := types.StringNull()
+	for _, u := range config.Users {
+		if u.Name == userName || userName == "" {
+			switch {
+			case u.User.Token != "":
+				token = types.StringValue(u.User.Token)
+				authMode = types.StringValue(kubeconfigAuthToken)
+			case u.User.ClientCertificateData != "" && u.User.ClientKeyData != "":
+				certificate, err := base64.StdEncoding.DecodeString(u.User.ClientCertificateData)
+				if err != nil {
+					resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Unable to decode client-certificate-data: %s", err))
+					return
+				}
+				key, err := base64.StdEncoding.DecodeString(u.User.ClientKeyData)
+				if err != nil {
+					resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("Unable to decode client-key-data: %s", err))
+					return
+				}
+				clientCertificate = types.StringValue(string(certificate))
+				clientKey = types.StringValue(string(key))
+				authMode = types.StringValue(kubeconfigAuthClientCert)
+			}
+			break
+		}
+	}
+
+	result, diags := types.ObjectValue(kubeconfigAttributeTypes, map[string]attr.Value{
+		"host":                   host,
+		"cluster_ca_certificate": caCertificate,
+		"token":                  token,
+		"client_certificate":     clientCertificate,
+		"client_key":             clientKey,
+		"auth_mode":              authMode,
+	})
+	resp.Error = function.FuncErrorFromDiags(ctx, diags)
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
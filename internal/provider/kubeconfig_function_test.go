@@ -0,0 +1,160 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestKubeconfigFunctionAuthMode(t *testing.T) {
+	encode := func(value string) string {
+		return base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	cases := map[string]struct {
+		user              string
+		expectedAuthMode  string
+		expectedToken     string
+		expectedClientKey string
+	}{
+		"token": {
+			user:             "token: secret",
+			expectedAuthMode: kubeconfigAuthToken,
+			expectedToken:    "secret",
+		},
+		"client certificate": {
+			user:              fmt.Sprintf("client-certificate-data: %s\n      client-key-data: %s", encode("CERT"), encode("KEY")),
+			expectedAuthMode:  kubeconfigAuthClientCert,
+			expectedClientKey: "KEY",
+		},
+		"no credentials": {
+			user: "{}",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			document := fmt.Sprintf(`clusters:
+  - name: test
+    cluster:
+      server: https://kube.example.com
+users:
+  - name: admin
+    user:
+      %s
+contexts:
+  - name: test
+    context:
+      cluster: test
+      user: admin
+current-context: test
+`, tc.user)
+
+			req := function.RunRequest{Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(document)})}
+			resp := function.RunResponse{Result: function.NewResultData(types.ObjectUnknown(kubeconfigAttributeTypes))}
+			KubeconfigFunction{}.Run(context.Background(), req, &resp)
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			result, ok := resp.Result.Value().(types.Object)
+			if !ok {
+				t.Fatalf("expected an object result, got %T", resp.Result.Value())
+			}
+			attributes := result.Attributes()
+			for attribute, expected := range map[string]string{
+				"auth_mode":  tc.expectedAuthMode,
+				"token":      tc.expectedToken,
+				"client_key": tc.expectedClientKey,
+			} {
+				value, ok := attributes[attribute].(types.String)
+				if !ok {
+					t.Fatalf("expected %s to be a string, got %T", attribute, attributes[attribute])
+				}
+				if expected == "" && !value.IsNull() {
+					t.Errorf("expected %s to be null, got %s", attribute, value)
+				}
+				if expected != "" && value.ValueString() != expected {
+					t.Errorf("expected %s %q, got %s", attribute, expected, value)
+				}
+			}
+		})
+	}
+}
+
+func TestKubeconfigFunctionCurrentContext(t *testing.T) {
+	cases := map[string]struct {
+		currentContext string
+		expectedHost   string
+		expectError    bool
+	}{
+		"current context": {
+			currentContext: "second",
+			expectedHost:   "https://second.example.com",
+		},
+		"no current context": {
+			expectedHost: "https://first.example.com",
+		},
+		"missing context": {
+			currentContext: "third",
+			expectError:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			document := fmt.Sprintf(`clusters:
+  - name: first
+    cluster:
+      server: https://first.example.com
+  - name: second
+    cluster:
+      server: https://second.example.com
+users:
+  - name: admin
+    user:
+      token: secret
+contexts:
+  - name: first
+    context:
+      cluster: first
+      user: admin
+  - name: second
+    context:
+      cluster: second
+      user: admin
+current-context: %q
+`, tc.currentContext)
+
+			req := function.RunRequest{Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(document)})}
+			resp := function.RunResponse{Result: function.NewResultData(types.ObjectUnknown(kubeconfigAttributeTypes))}
+			KubeconfigFunction{}.Run(context.Background(), req, &resp)
+			if tc.expectError {
+				if resp.Error == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("unexpected error: %s", resp.Error)
+			}
+
+			result, ok := resp.Result.Value().(types.Object)
+			if !ok {
+				t.Fatalf("expected an object result, got %T", resp.Result.Value())
+			}
+			host, ok := result.Attributes()["host"].(types.String)
+			if !ok || host.ValueString() != tc.expectedHost {
+				t.Errorf("expected host %q, got %s", tc.expectedHost, result.Attributes()["host"])
+			}
+		})
+	}
+}
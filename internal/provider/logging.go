@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// maxLoggedBodyLength bounds how much of a body ends up in debug logs.
+const maxLoggedBodyLength = 1000
+
+// sensitiveBodyKeys are substrings of JSON keys whose values are masked in debug logs.
+var sensitiveBodyKeys = []string{"keypair", "key_pair", "password", "secret", "token", "private_key", "credential"}
+
+// redactBody returns a printable version of a JSON request or response body with
+// the values of sensitive keys masked. Bodies that aren't JSON are not logged at all.
+func redactBody(body []byte) string {
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Sprintf("[REDACTED non-JSON body, %d bytes]", len(body))
+	}
+
+	redacted, err := json.Marshal(redactValue(payload))
+	if err != nil {
+		return fmt.Sprintf("[REDACTED body, %d bytes]", len(body))
+	}
+
+	return string(redacted)
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			if isSensitiveKey(key) {
+				v[key] = "[REDACTED]"
+			} else {
+				v[key] = redactValue(nested)
+			}
+		}
+	case []any:
+		for i, nested := range v {
+			v[i] = redactValue(nested)
+		}
+	}
+
+	return value
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveBodyKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isExtraHeader reports whether name is one of the configured extra headers, whose
+// values are masked in debug logs as they may carry credentials.
+func isExtraHeader(extraHeaders map[string]string, name string) bool {
+	for extra := range extraHeaders {
+		if strings.EqualFold(extra, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// peekBody reads at most limit+1 bytes of body, enough to tell whether it is longer
+// than limit, and returns them along with a body replaying them before the rest, so
+// large bodies are not buffered in memory just to be logged.
+func peekBody(body io.ReadCloser, limit int) ([]byte, io.ReadCloser, error) {
+	prefix, err := io.ReadAll(io.LimitReader(body, int64(limit)+1))
+	rest := struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), body), body}
+
+	return prefix, rest, err
+}
+
+// redactBodyPrefix is redactBody for a body of which only the first limit+1 bytes
+// were read. Longer bodies can't be parsed, and thus redacted, so they are not logged.
+func redactBodyPrefix(prefix []byte, limit int) string {
+	if len(prefix) > limit {
+		return fmt.Sprintf("[REDACTED body over %d bytes]", limit)
+	}
+
+	bodyStr := redactBody(prefix)
+	if len(bodyStr) > limit {
+		bodyStr = bodyStr[:limit] + "... [truncated]"
+	}
+
+	return bodyStr
+}
+
+// statusLogger logs the status changes observed while polling an object, so that
+// TF_LOG=INFO shows the progress of long running operations.
+type statusLogger struct {
+	kind   string
+	id     string
+	status string
+}
+
+func newStatusLogger(kind, id string) *statusLogger {
+	return &statusLogger{kind: kind, id: id}
+}
+
+// observe logs status if it differs from the previously observed one.
+func (l *statusLogger) observe(ctx context.Context, status string) {
+	if status == l.status {
+		return
+	}
+
+	fields := map[string]any{"id": l.id, "status": status}
+	if l.status == "" {
+		tflog.Info(ctx, fmt.Sprintf("%s %s is %s", l.kind, l.id, status), fields)
+	} else {
+		fields["previous_status"] = l.status
+		tflog.Info(ctx, fmt.Sprintf("%s %s status changed from %s to %s", l.kind, l.id, l.status, status), fields)
+	}
+	l.status = status
+}
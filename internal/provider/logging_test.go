@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	cases := map[string]struct {
+		body     string
+		expected string
+	}{
+		"sensitive keys": {
+			body:     `{"name":"prod","keypair":"ops","node_count":3}`,
+			expected: `{"keypair":"[REDACTED]","name":"prod","node_count":3}`,
+		},
+		"nested and case insensitive": {
+			body:     `{"pools":[{"Key_Pair":"ops","name":"a"}],"auth":{"client_secret":"s"}}`,
+			expected: `{"auth":{"client_secret":"[REDACTED]"},"pools":[{"Key_Pair":"[REDACTED]","name":"a"}]}`,
+		},
+		"not json": {
+			body:     `keypair=ops`,
+			expected: `[REDACTED non-JSON body, 11 bytes]`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := redactBody([]byte(tc.body)); got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPeekBody(t *testing.T) {
+	cases := map[string]struct {
+		body           string
+		limit          int
+		expectedPrefix string
+		expectedLog    string
+	}{
+		"short body": {
+			body:           `{"name":"prod","keypair":"ops"}`,
+			limit:          maxLoggedBodyLength,
+			expectedPrefix: `{"name":"prod","keypair":"ops"}`,
+			expectedLog:    `{"keypair":"[REDACTED]","name":"prod"}`,
+		},
+		"long body": {
+			body:           `{"name":"` + strings.Repeat("a", 64) + `"}`,
+			limit:          10,
+			expectedPrefix: `{"name":"aa`,
+			expectedLog:    `[REDACTED body over 10 bytes]`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			prefix, body, err := peekBody(io.NopCloser(strings.NewReader(tc.body)), tc.limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if string(prefix) != tc.expectedPrefix {
+				t.Errorf("expected prefix %s, got %s", tc.expectedPrefix, prefix)
+			}
+			if got := redactBodyPrefix(prefix, tc.limit); got != tc.expectedLog {
+				t.Errorf("expected %s to be logged, got %s", tc.expectedLog, got)
+			}
+
+			replayed, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if string(replayed) != tc.body {
+				t.Errorf("expected the full body to be replayed, got %s", replayed)
+			}
+		})
+	}
+}
+
+func TestIsExtraHeader(t *testing.T) {
+	extraHeaders := map[string]string{"X-Gateway-Key": "secret"}
+
+	cases := map[string]struct {
+		name     string
+		expected bool
+	}{
+		"extra header":    {name: "X-Gateway-Key", expected: true},
+		"canonicalized":   {name: "x-gateway-key", expected: true},
+		"provider header": {name: "User-Agent", expected: false},
+		"empty name":      {name: "", expected: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := isExtraHeader(extraHeaders, tc.name); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
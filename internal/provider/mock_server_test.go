@@ -0,0 +1,457 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/QumulusTechnology/strato-project/sdk"
+)
+
+// mockStratoServer is an in-memory stand-in for the Strato API. Clusters and node
+// pools stay in their transitional status (in progress, creating, resizing,
+// deleting) for settleAfter reads before settling, so that the polling state
+// machines of the resources can be exercised without a real backend.
+type mockStratoServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	nextID    int
+	clusters  map[string]*mockCluster
+	nodePools map[string]*mockNodePool
+	requests  []string
+
+	// settleAfter is the number of reads an object stays in a transitional status.
+	settleAfter int
+	// clusterStatus and nodePoolStatus are the statuses objects settle in
+	// once created or resized, ready unless a test wants an error.
+	clusterStatus  string
+	nodePoolStatus string
+	// ignoreDeletes accepts delete requests without acting on them.
+	ignoreDeletes bool
+	// conflictingCreates is the number of node pool creations rejected with 409.
+	conflictingCreates int
+	// conflictingUpdates is the number of cluster and node pool updates rejected with 409.
+	conflictingUpdates int
+	// failingCreates is the number of clusters created that settle in error state
+	// regardless of clusterStatus.
+	failingCreates int
+	// emptyNodePoolLists is the number of node pool listings answered with an empty list.
+	emptyNodePoolLists int
+	// unpropagatedReads is the number of reads of a new cluster answered with 404.
+	unpropagatedReads int
+}
+
+type mockCluster struct {
+	id              string
+	clusterID       string
+	projectID       string
+	name            string
+	keypair         string
+	tags            []string
+	status          string
+	reads           int
+	createdAt       int64
+	updatedAt       int64
+	defaultNodePool string
+	failing         bool
+	unpropagated    int
+}
+
+type mockNodePool struct {
+	id         string
+	clusterID  string
+	name       string
+	flavorID   string
+	networkID  string
+	keyPair    string
+	volumeSize int64
+	nodeCount  int64
+	isDefault  bool
+	status     string
+	reads      int
+}
+
+// newMockStratoServer starts a mock Strato API for the duration of the test and
+// shortens the resources' poll interval accordingly.
+func newMockStratoServer(t *testing.T) *mockStratoServer {
+	t.Helper()
+
+	m := &mockStratoServer{
+		clusters:       map[string]*mockCluster{},
+		nodePools:      map[string]*mockNodePool{},
+		settleAfter:    2,
+		clusterStatus:  string(sdk.CLUSTER_STATUS_READY),
+		nodePoolStatus: string(sdk.NODE_POOL_STATUS_READY),
+	}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	t.Cleanup(m.Close)
+
+	interval := pollInterval
+	pollInterval = time.Millisecond
+	t.Cleanup(func() { pollInterval = interval })
+
+	return m
+}
+
+// client returns an SDK client talking to the mock server.
+func (m *mockStratoServer) client(t *testing.T) *sdk.ClientWithResponses {
+	t.Helper()
+
+	client, err := sdk.NewClientWithResponses(m.URL + "/")
+	if err != nil {
+		t.Fatalf("unable to create client: %s", err)
+	}
+
+	return client
+}
+
+// addCluster registers a ready cluster, for tests that only need a parent for node pools.
+func (m *mockStratoServer) addCluster(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clusters[id] = &mockCluster{id: id, name: id, status: string(sdk.CLUSTER_STATUS_READY)}
+}
+
+// requestCount returns how many requests with the given method were received.
+func (m *mockStratoServer) requestCount(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for _, request := range m.requests {
+		if strings.HasPrefix(request, method+" ") {
+			count++
+		}
+	}
+
+	return count
+}
+
+// nodePoolListCount returns the number of node pool listings received.
+func (m *mockStratoServer) nodePoolListCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for _, request := range m.requests {
+		// A listing ends with the node pools segment, showing a pool with its id
+		requestPath, ok := strings.CutPrefix(request, http.MethodGet+" ")
+		if ok && strings.Contains(path.Base(requestPath), "pool") && !strings.Contains(path.Base(path.Dir(requestPath)), "pool") {
+			count++
+		}
+	}
+
+	return count
+}
+
+// serveHTTP routes requests on the path segments naming clusters and node pools
+// rather than on exact paths, so the mock does not depend on the API prefix.
+func (m *mockStratoServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests = append(m.requests, r.Method+" "+r.URL.Path)
+
+	var clusterID, nodePoolID string
+	isNodePool := false
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	for i := 0; i < len(segments); i++ {
+		next := ""
+		if i+1 < len(segments) {
+			next = segments[i+1]
+		}
+		switch {
+		case strings.Contains(segments[i], "pool"):
+			isNodePool = true
+			nodePoolID = next
+			i++
+		case strings.Contains(segments[i], "cluster") && !strings.Contains(next, "pool"):
+			clusterID = next
+			i++
+		}
+	}
+
+	if isNodePool {
+		m.serveNodePool(w, r, clusterID, nodePoolID)
+	} else {
+		m.serveCluster(w, r, clusterID)
+	}
+}
+
+func (m *mockStratoServer) serveCluster(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method == http.MethodPost && id == "" {
+		var body sdk.CreateClusterJSONRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeMockError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		m.nextID++
+		cluster := &mockCluster{
+			id:           fmt.Sprintf("cluster-%d", m.nextID),
+			clusterID:    r.Header.Get("X-OS-Cluster-ID"),
+			projectID:    r.Header.Get("X-OS-Project-ID"),
+			name:         body.Name,
+			keypair:      body.Keypair,
+			status:       string(sdk.CLUSTER_STATUS_IN_PROGRESS),
+			createdAt:    time.Now().Unix(),
+			unpropagated: m.unpropagatedReads,
+		}
+		if m.failingCreates > 0 {
+			m.failingCreates--
+			cluster.failing = true
+		}
+		if body.Tags != nil {
+			cluster.tags = *body.Tags
+		}
+		m.clusters[cluster.id] = cluster
+		nodePool := &mockNodePool{
+			id:         fmt.Sprintf("node-pool-%d", m.nextID),
+			clusterID:  cluster.id,
+			name:       body.Name + "-default",
+			flavorID:   body.FlavorID,
+			networkID:  body.NetworkID,
+			keyPair:    body.Keypair,
+			volumeSize: body.VolumeSize,
+			nodeCount:  body.NodeCount,
+			isDefault:  true,
+			status:     string(sdk.NODE_POOL_STATUS_READY),
+		}
+		m.nodePools[nodePool.id] = nodePool
+		cluster.defaultNodePool = nodePool.id
+		writeMockJSON(w, m.clusterBody(cluster))
+		return
+	}
+
+	cluster, ok := m.clusters[id]
+	if !ok {
+		writeMockError(w, http.StatusNotFound, "cluster not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if cluster.unpropagated > 0 {
+			cluster.unpropagated--
+			writeMockError(w, http.StatusNotFound, "cluster not found")
+			return
+		}
+		cluster.reads++
+		if cluster.reads > m.settleAfter {
+			switch cluster.status {
+			case string(sdk.CLUSTER_STATUS_IN_PROGRESS):
+				cluster.status = m.clusterStatus
+				if cluster.failing {
+					cluster.status = string(sdk.CLUSTER_STATUS_ERROR)
+				}
+			case string(sdk.CLUSTER_STATUS_DELETING):
+				delete(m.clusters, id)
+				writeMockError(w, http.StatusNotFound, "cluster not found")
+				return
+			}
+		}
+	case http.MethodPut, http.MethodPatch, http.MethodPost:
+		if m.conflictingUpdates > 0 {
+			m.conflictingUpdates--
+			writeMockError(w, http.StatusConflict, "cluster is busy")
+			return
+		}
+		var body sdk.UpdateClusterJSONRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeMockError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		nodePool := m.nodePools[cluster.defaultNodePool]
+		if nodePool.nodeCount != body.NodeCount {
+			nodePool.nodeCount = body.NodeCount
+			nodePool.status = string(sdk.NODE_POOL_STATUS_RESIZING)
+			nodePool.reads = 0
+		}
+		cluster.updatedAt = time.Now().Unix()
+	case http.MethodDelete:
+		if !m.ignoreDeletes {
+			cluster.status = string(sdk.CLUSTER_STATUS_DELETING)
+			cluster.reads = 0
+		}
+	}
+
+	writeMockJSON(w, m.clusterBody(cluster))
+}
+
+func (m *mockStratoServer) serveNodePool(w http.ResponseWriter, r *http.Request, clusterID, id string) {
+	if id == "" {
+		if _, ok := m.clusters[clusterID]; !ok {
+			writeMockError(w, http.StatusNotFound, "cluster not found")
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			list := []any{}
+			if m.emptyNodePoolLists > 0 {
+				m.emptyNodePoolLists--
+				writeMockJSON(w, list)
+				return
+			}
+			for _, nodePool := range m.nodePools {
+				if nodePool.clusterID == clusterID {
+					list = append(list, m.nodePoolBody(nodePool))
+				}
+			}
+			writeMockJSON(w, list)
+		case http.MethodPost:
+			if m.conflictingCreates > 0 {
+				m.conflictingCreates--
+				writeMockError(w, http.StatusConflict, "cluster is busy")
+				return
+			}
+			var body sdk.CreateNodepoolJSONRequestBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeMockError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			m.nextID++
+			nodePool := &mockNodePool{
+				id:         fmt.Sprintf("node-pool-%d", m.nextID),
+				clusterID:  clusterID,
+				name:       body.Name,
+				flavorID:   body.FlavorID,
+				networkID:  body.NetworkID,
+				keyPair:    body.Keypair,
+				volumeSize: body.VolumeSize,
+				nodeCount:  body.NodeCount,
+				status:     string(sdk.NODE_POOL_STATUS_CREATING),
+			}
+			m.nodePools[nodePool.id] = nodePool
+			writeMockJSON(w, m.nodePoolBody(nodePool))
+		default:
+			writeMockError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	nodePool, ok := m.nodePools[id]
+	if !ok {
+		writeMockError(w, http.StatusNotFound, "node pool not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		nodePool.reads++
+		if nodePool.reads > m.settleAfter {
+			switch nodePool.status {
+			case string(sdk.NODE_POOL_STATUS_CREATING), string(sdk.NODE_POOL_STATUS_RESIZING):
+				nodePool.status = m.nodePoolStatus
+			case string(sdk.NODE_POOL_STATUS_DELETING):
+				delete(m.nodePools, id)
+				writeMockError(w, http.StatusNotFound, "node pool not found")
+				return
+			}
+		}
+	case http.MethodPut, http.MethodPatch, http.MethodPost:
+		if m.conflictingUpdates > 0 {
+			m.conflictingUpdates--
+			writeMockError(w, http.StatusConflict, "cluster is busy")
+			return
+		}
+		var body sdk.UpdateNodepoolJSONRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeMockError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		nodePool.nodeCount = body.NodeCount
+		nodePool.status = string(sdk.NODE_POOL_STATUS_RESIZING)
+		nodePool.reads = 0
+	case http.MethodDelete:
+		if !m.ignoreDeletes {
+			nodePool.status = string(sdk.NODE_POOL_STATUS_DELETING)
+			nodePool.reads = 0
+		}
+	}
+
+	writeMockJSON(w, m.nodePoolBody(nodePool))
+}
+
+// clusterBody renders a cluster as the SDK type returned by ShowCluster, so that
+// the mock always uses the same JSON field names as the SDK.
+func (m *mockStratoServer) clusterBody(cluster *mockCluster) any {
+	body := newMockBody((&sdk.ShowClusterResponse{}).JSON200)
+	body.Id = cluster.id
+	body.Name = cluster.name
+	body.ClusterID = cluster.clusterID
+	body.ProjectID = cluster.projectID
+	body.Keypair = cluster.keypair
+	if len(cluster.tags) > 0 {
+		body.Tags = &cluster.tags
+	}
+	body.Status = cluster.status
+	body.Phase = mockClusterPhase(cluster)
+	body.CreatedAt = cluster.createdAt
+	body.UpdatedAt = cluster.updatedAt
+
+	return body
+}
+
+// mockClusterPhase derives the phase of a cluster from its status. Clusters in
+// progress are provisioned after their first read, before they are ready.
+func mockClusterPhase(cluster *mockCluster) string {
+	switch cluster.status {
+	case string(sdk.CLUSTER_STATUS_IN_PROGRESS):
+		if cluster.reads > 1 {
+			return string(CLUSTER_PHASE_PROVISIONED)
+		}
+		return string(CLUSTER_PHASE_PROVISIONING)
+	case string(sdk.CLUSTER_STATUS_ERROR):
+		return string(CLUSTER_PHASE_FAILED)
+	case string(sdk.CLUSTER_STATUS_DELETING):
+		return string(CLUSTER_PHASE_DELETING)
+	default:
+		return string(CLUSTER_PHASE_PROVISIONED)
+	}
+}
+
+// nodePoolBody renders a node pool as the SDK type returned by ShowNodePool.
+func (m *mockStratoServer) nodePoolBody(nodePool *mockNodePool) any {
+	body := newMockBody((&sdk.ShowNodePoolResponse{}).JSON200)
+	body.Id = nodePool.id
+	body.ClusterID = nodePool.clusterID
+	body.Name = nodePool.name
+	body.ServerGroupID = "server-group-" + nodePool.id
+	body.FlavorID = nodePool.flavorID
+	body.NetworkID = nodePool.networkID
+	body.KeyPair = nodePool.keyPair
+	body.VolumeSize = nodePool.volumeSize
+	body.NodeCount = nodePool.nodeCount
+	body.IsDefault = nodePool.isDefault
+	body.Status = nodePool.status
+
+	return body
+}
+
+// newMockBody allocates a value of the type a response's JSON200 field points to,
+// which avoids depending on the names of the generated SDK models.
+func newMockBody[T any](_ *T) *T {
+	return new(T)
+}
+
+func writeMockJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeMockError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ConfigValidator = nodeCountBoundsValidator{}
+
+// nodeCountBoundsValidator rejects autoscaling configs where min_node_count is greater
+// than max_node_count, or where node_count falls outside of [min_node_count, max_node_count].
+// Unset and unknown values are not checked.
+//
+// It is not wired up yet: the node pool resource has no min_node_count and
+// max_node_count attributes until the API supports autoscaling. Once it does, return
+// it from a NodePoolResource.ConfigValidators method and assert
+// resource.ResourceWithConfigValidators.
+type nodeCountBoundsValidator struct{}
+
+func (v nodeCountBoundsValidator) Description(ctx context.Context) string {
+	return "min_node_count must not be greater than max_node_count, and node_count must be between them"
+}
+
+func (v nodeCountBoundsValidator) MarkdownDescription(ctx context.Context) string {
+	return "`min_node_count` must not be greater than `max_node_count`, and `node_count` must be between them"
+}
+
+func (v nodeCountBoundsValidator) ValidateResource(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var nodeCount, minNodeCount, maxNodeCount types.Int64
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("node_count"), &nodeCount)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("min_node_count"), &minNodeCount)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("max_node_count"), &maxNodeCount)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	known := func(value types.Int64) bool {
+		return !value.IsNull() && !value.IsUnknown()
+	}
+
+	if known(minNodeCount) && known(maxNodeCount) && minNodeCount.ValueInt64() > maxNodeCount.ValueInt64() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("min_node_count"),
+			"Invalid node count bounds",
+			fmt.Sprintf("min_node_count (%d) must not be greater than max_node_count (%d).", minNodeCount.ValueInt64(), maxNodeCount.ValueInt64()),
+		)
+		return
+	}
+
+	if !known(nodeCount) {
+		return
+	}
+
+	if known(minNodeCount) && nodeCount.ValueInt64() < minNodeCount.ValueInt64() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("node_count"),
+			"Invalid node count",
+			fmt.Sprintf("node_count (%d) must not be less than min_node_count (%d).", nodeCount.ValueInt64(), minNodeCount.ValueInt64()),
+		)
+	}
+
+	if known(maxNodeCount) && nodeCount.ValueInt64() > maxNodeCount.ValueInt64() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("node_count"),
+			"Invalid node count",
+			fmt.Sprintf("node_count (%d) must not be greater than max_node_count (%d).", nodeCount.ValueInt64(), maxNodeCount.ValueInt64()),
+		)
+	}
+}
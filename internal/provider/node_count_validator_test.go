@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestNodeCountBoundsValidator(t *testing.T) {
+	type model struct {
+		NodeCount    types.Int64 `tfsdk:"node_count"`
+		MinNodeCount types.Int64 `tfsdk:"min_node_count"`
+		MaxNodeCount types.Int64 `tfsdk:"max_node_count"`
+	}
+
+	s := schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"node_count":     schema.Int64Attribute{Optional: true},
+			"min_node_count": schema.Int64Attribute{Optional: true},
+			"max_node_count": schema.Int64Attribute{Optional: true},
+		},
+	}
+
+	cases := map[string]struct {
+		data          model
+		expectedPaths []path.Path
+	}{
+		"within bounds": {
+			data: model{NodeCount: types.Int64Value(2), MinNodeCount: types.Int64Value(1), MaxNodeCount: types.Int64Value(3)},
+		},
+		"unset bounds": {
+			data: model{NodeCount: types.Int64Value(2), MinNodeCount: types.Int64Null(), MaxNodeCount: types.Int64Null()},
+		},
+		"unknown max": {
+			data: model{NodeCount: types.Int64Value(5), MinNodeCount: types.Int64Value(1), MaxNodeCount: types.Int64Unknown()},
+		},
+		"min greater than max": {
+			data:          model{NodeCount: types.Int64Value(2), MinNodeCount: types.Int64Value(4), MaxNodeCount: types.Int64Value(3)},
+			expectedPaths: []path.Path{path.Root("min_node_count")},
+		},
+		"below min": {
+			data:          model{NodeCount: types.Int64Value(1), MinNodeCount: types.Int64Value(2), MaxNodeCount: types.Int64Value(3)},
+			expectedPaths: []path.Path{path.Root("node_count")},
+		},
+		"above max": {
+			data:          model{NodeCount: types.Int64Value(4), MinNodeCount: types.Int64Null(), MaxNodeCount: types.Int64Value(3)},
+			expectedPaths: []path.Path{path.Root("node_count")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, config := testPlan(t, s, tc.data)
+
+			var resp resource.ValidateConfigResponse
+			nodeCountBoundsValidator{}.ValidateResource(context.Background(), resource.ValidateConfigRequest{Config: config}, &resp)
+
+			if resp.Diagnostics.ErrorsCount() != len(tc.expectedPaths) {
+				t.Fatalf("expected %d errors, got: %v", len(tc.expectedPaths), resp.Diagnostics)
+			}
+			for i, d := range resp.Diagnostics.Errors() {
+				withPath, ok := d.(interface{ Path() path.Path })
+				if !ok || !withPath.Path().Equal(tc.expectedPaths[i]) {
+					t.Errorf("expected error on %s, got: %v", tc.expectedPaths[i], d)
+				}
+			}
+		})
+	}
+}
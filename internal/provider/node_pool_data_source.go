@@ -6,6 +6,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -28,25 +29,30 @@ type NodePoolDataSource struct {
 
 // ClusterDataSourceModel describes the data source data model.
 type NodePoolDataSourceModel struct {
-	Id            types.String `tfsdk:"id"`
-	ServerGroupId types.String `tfsdk:"server_group_id"`
-	ClusterId     types.String `tfsdk:"cluster_id"`
-	Name          types.String `tfsdk:"name"`
-	FlavorId      types.String `tfsdk:"flavor_id"`
-	NetworkId     types.String `tfsdk:"network_id"`
-	KeyPair       types.String `tfsdk:"key_pair"`
-	VolumeSize    types.Int64  `tfsdk:"volume_size"`
-	IsDefault     types.Bool   `tfsdk:"is_default"`
-	NodeCount     types.Int64  `tfsdk:"node_count"`
-	MaxNodeCount  types.Int64  `tfsdk:"max_node_count"`
-	MinNodeCount  types.Int64  `tfsdk:"min_node_count"`
-	AutoScale     types.Bool   `tfsdk:"auto_scale"`
-	Status        types.String `tfsdk:"status"`
-	LastErrorId   types.String `tfsdk:"last_error_id"`
-	CreatedAt     types.Int64  `tfsdk:"created_at"`
-	UpdatedAt     types.Int64  `tfsdk:"updated_at"`
-	Deleted       types.Bool   `tfsdk:"deleted"`
-	DeletedAt     types.Int64  `tfsdk:"deleted_at"`
+	Id               types.String `tfsdk:"id"`
+	ServerGroupId    types.String `tfsdk:"server_group_id"`
+	ClusterId        types.String `tfsdk:"cluster_id"`
+	Name             types.String `tfsdk:"name"`
+	FlavorId         types.String `tfsdk:"flavor_id"`
+	NetworkId        types.String `tfsdk:"network_id"`
+	KeyPair          types.String `tfsdk:"key_pair"`
+	VolumeSize       types.Int64  `tfsdk:"volume_size"`
+	IsDefault        types.Bool   `tfsdk:"is_default"`
+	NodeCount        types.Int64  `tfsdk:"node_count"`
+	MaxNodeCount     types.Int64  `tfsdk:"max_node_count"`
+	MinNodeCount     types.Int64  `tfsdk:"min_node_count"`
+	AutoScale        types.Bool   `tfsdk:"auto_scale"`
+	Status           types.String `tfsdk:"status"`
+	Ready            types.Bool   `tfsdk:"ready"`
+	LastErrorId      types.String `tfsdk:"last_error_id"`
+	CreatedAt        types.Int64  `tfsdk:"created_at"`
+	UpdatedAt        types.Int64  `tfsdk:"updated_at"`
+	Deleted          types.Bool   `tfsdk:"deleted"`
+	DeletedAt        types.Int64  `tfsdk:"deleted_at"`
+	CreatedAtRFC3339 types.String `tfsdk:"created_at_rfc3339"`
+	UpdatedAtRFC3339 types.String `tfsdk:"updated_at_rfc3339"`
+	DeletedAtRFC3339 types.String `tfsdk:"deleted_at_rfc3339"`
+	AgeSeconds       types.Int64  `tfsdk:"age_seconds"`
 }
 
 func (d *NodePoolDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -101,11 +107,11 @@ func (d *NodePoolDataSource) Schema(ctx context.Context, req datasource.SchemaRe
 				Computed:            true,
 			},
 			"max_node_count": schema.Int64Attribute{
-				MarkdownDescription: "Max node count",
+				MarkdownDescription: "Max node count, null when the API does not report autoscaling bounds",
 				Computed:            true,
 			},
 			"min_node_count": schema.Int64Attribute{
-				MarkdownDescription: "Min node count",
+				MarkdownDescription: "Min node count, null when the API does not report autoscaling bounds",
 				Computed:            true,
 			},
 			"auto_scale": schema.BoolAttribute{
@@ -116,6 +122,10 @@ func (d *NodePoolDataSource) Schema(ctx context.Context, req datasource.SchemaRe
 				MarkdownDescription: "Status",
 				Computed:            true,
 			},
+			"ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether the node pool status is ready",
+				Computed:            true,
+			},
 			"last_error_id": schema.StringAttribute{
 				MarkdownDescription: "Last error identifier",
 				Computed:            true,
@@ -137,6 +147,22 @@ func (d *NodePoolDataSource) Schema(ctx context.Context, req datasource.SchemaRe
 				Computed:            true,
 				Optional:            true,
 			},
+			"created_at_rfc3339": schema.StringAttribute{
+				MarkdownDescription: "Created at, as an RFC 3339 timestamp",
+				Computed:            true,
+			},
+			"updated_at_rfc3339": schema.StringAttribute{
+				MarkdownDescription: "Updated at, as an RFC 3339 timestamp",
+				Computed:            true,
+			},
+			"deleted_at_rfc3339": schema.StringAttribute{
+				MarkdownDescription: "Deleted at, as an RFC 3339 timestamp",
+				Computed:            true,
+			},
+			"age_seconds": schema.Int64Attribute{
+				MarkdownDescription: "Age in seconds, from `created_at` to the time the data source was read",
+				Computed:            true,
+			},
 		},
 	}
 }
@@ -147,17 +173,17 @@ func (d *NodePoolDataSource) Configure(ctx context.Context, req datasource.Confi
 		return
 	}
 
-	client, ok := req.ProviderData.(*sdk.ClientWithResponses)
+	data, ok := req.ProviderData.(*stratoDataSourceData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *sdk.ClientWithResponses, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *stratoDataSourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = client
+	d.client = data.client
 }
 
 func (d *NodePoolDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -170,18 +196,20 @@ func (d *NodePoolDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
+	// Note: node pools cannot be looked up by name, the API adds a generated suffix to
+	// the name a pool is created with, so the logical name matches no full name exactly
 	showResult, err := d.client.ShowNodePoolWithResponse(ctx, data.ClusterId.ValueString(), data.Id.ValueString(), &sdk.ShowNodePoolParams{})
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to read node pool", err.Error())
+		addAPIError(&resp.Diagnostics, "Unable to read node pool", err)
 		return
 	}
 	if showResult.StatusCode() != 200 {
-		resp.Diagnostics.AddError("Unable to read node pool", fmt.Sprintf("http response status code: %d", showResult.StatusCode()))
+		addAPIError(&resp.Diagnostics, "Unable to read node pool", newStatusError(showResult.HTTPResponse, showResult.Body))
 		return
 	}
 	nodePool := showResult.JSON200
 	if nodePool == nil {
-		resp.Diagnostics.AddError("Unable to read node pool", "node pool is nil")
+		addAPIError(&resp.Diagnostics, "Unable to read node pool", missingBodyError(showResult.HTTPResponse, showResult.Body, errNodePoolNil))
 		return
 	}
 
@@ -194,10 +222,18 @@ func (d *NodePoolDataSource) Read(ctx context.Context, req datasource.ReadReques
 	data.VolumeSize = types.Int64Value(nodePool.VolumeSize)
 	data.IsDefault = types.BoolValue(nodePool.IsDefault)
 	data.NodeCount = types.Int64Value(nodePool.NodeCount)
-	data.MaxNodeCount = types.Int64Value(nodePool.MaxNodeCount)
-	data.MinNodeCount = types.Int64Value(nodePool.MinNodeCount)
+	// The autoscaling bounds are plain integers in the response, so an unpopulated
+	// maximum comes back as zero; report the bounds as unset rather than as real values
+	if nodePool.MaxNodeCount > 0 {
+		data.MaxNodeCount = types.Int64Value(nodePool.MaxNodeCount)
+		data.MinNodeCount = types.Int64Value(nodePool.MinNodeCount)
+	} else {
+		data.MaxNodeCount = types.Int64Null()
+		data.MinNodeCount = types.Int64Null()
+	}
 	data.AutoScale = types.BoolValue(nodePool.AutoScale)
 	data.Status = types.StringValue(nodePool.Status)
+	data.Ready = types.BoolValue(nodePool.Status == string(sdk.NODE_POOL_STATUS_READY))
 	data.LastErrorId = types.StringValue(nodePool.LastErrorID)
 	data.CreatedAt = types.Int64Value(nodePool.CreatedAt)
 	data.UpdatedAt = types.Int64Value(nodePool.UpdatedAt)
@@ -207,6 +243,13 @@ func (d *NodePoolDataSource) Read(ctx context.Context, req datasource.ReadReques
 	} else {
 		data.DeletedAt = types.Int64Null()
 	}
+	data.CreatedAtRFC3339 = epochToRFC3339(nodePool.CreatedAt)
+	data.AgeSeconds = ageSeconds(nodePool.CreatedAt, time.Now())
+	data.UpdatedAtRFC3339 = epochToRFC3339(nodePool.UpdatedAt)
+	data.DeletedAtRFC3339 = types.StringNull()
+	if nodePool.DeletedAt != nil {
+		data.DeletedAtRFC3339 = epochToRFC3339(*nodePool.DeletedAt)
+	}
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
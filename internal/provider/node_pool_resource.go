@@ -5,15 +5,19 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"time"
+	"regexp"
 
 	"github.com/avast/retry-go/v4"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
 	"github.com/QumulusTechnology/strato-project/sdk"
@@ -29,7 +33,9 @@ func NewNodePoolResource() resource.Resource {
 
 // NodePoolResource defines the resource implementation.
 type NodePoolResource struct {
-	client *sdk.ClientWithResponses
+	client         *sdk.ClientWithResponses
+	defaultKeypair string
+	polls          pollSemaphore
 }
 
 // NodePoolResourceModel describes the resource data model.
@@ -45,19 +51,26 @@ type NodePoolResourceModel struct {
 	NodeCount  types.Int64  `tfsdk:"node_count"`
 
 	// optional attributes
+	ChangeReason    types.String `tfsdk:"change_reason"`
+	WaitForReady    types.Bool   `tfsdk:"wait_for_ready"`
+	StabilizeOnRead types.Bool   `tfsdk:"stabilize_on_read"`
 	// AutoScale    types.Bool  `tfsdk:"auto_scale"`
 	// MinNodeCount types.Int64 `tfsdk:"min_node_count"`
 	// MaxNodeCount types.Int64 `tfsdk:"max_node_count"`
 
 	// computed attributes
-	ServerGroupId types.String `tfsdk:"server_group_id"`
-	IsDefault     types.Bool   `tfsdk:"is_default"`
-	Status        types.String `tfsdk:"status"`
-	LastErrorId   types.String `tfsdk:"last_error_id"`
-	CreatedAt     types.Int64  `tfsdk:"created_at"`
-	UpdatedAt     types.Int64  `tfsdk:"updated_at"`
-	Deleted       types.Bool   `tfsdk:"deleted"`
-	DeletedAt     types.Int64  `tfsdk:"deleted_at"`
+	ServerGroupId    types.String `tfsdk:"server_group_id"`
+	IsDefault        types.Bool   `tfsdk:"is_default"`
+	Status           types.String `tfsdk:"status"`
+	Ready            types.Bool   `tfsdk:"ready"`
+	LastErrorId      types.String `tfsdk:"last_error_id"`
+	CreatedAt        types.Int64  `tfsdk:"created_at"`
+	UpdatedAt        types.Int64  `tfsdk:"updated_at"`
+	Deleted          types.Bool   `tfsdk:"deleted"`
+	DeletedAt        types.Int64  `tfsdk:"deleted_at"`
+	CreatedAtRFC3339 types.String `tfsdk:"created_at_rfc3339"`
+	UpdatedAtRFC3339 types.String `tfsdk:"updated_at_rfc3339"`
+	DeletedAtRFC3339 types.String `tfsdk:"deleted_at_rfc3339"`
 }
 
 func (r *NodePoolResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -84,31 +97,60 @@ func (r *NodePoolResource) Schema(ctx context.Context, req resource.SchemaReques
 				Required:            true,
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "Node pool name (NOTE: will be normalized by the API, use the `full_name` attribute to see the actual name)",
+				MarkdownDescription: "Node pool name, which must contain a letter or digit and not start or end with whitespace (NOTE: the API adds a generated suffix, use the `full_name` attribute to see the actual name once the pool is created)",
 				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(trimmedNameRegexp, "must not be blank or start or end with whitespace"),
+					stringvalidator.RegexMatches(nameCharacterRegexp, "must contain a letter or digit, as other characters are replaced when the API normalizes the name"),
+				},
 			},
 			"flavor_id": schema.StringAttribute{
 				MarkdownDescription: "OpenStack flavor id",
 				Required:            true,
+				Validators:          flavorIdValidators,
 			},
 			"network_id": schema.StringAttribute{
-				MarkdownDescription: "OpenStack network id",
-				Required:            true,
-			},
-			"key_pair": schema.StringAttribute{
-				MarkdownDescription: "OpenStack keypair",
+				MarkdownDescription: "OpenStack network id (UUID)",
 				Required:            true,
+				Validators:          networkIdValidators,
 			},
 			"volume_size": schema.Int64Attribute{
-				MarkdownDescription: "Node worker volume size in GB",
+				MarkdownDescription: fmt.Sprintf("Node worker volume size in GB (minimum %d)", minVolumeSize),
 				Required:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(minVolumeSize),
+				},
 			},
 			"node_count": schema.Int64Attribute{
 				MarkdownDescription: "Number of node workers",
 				Required:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
 
 			// optional attributes
+			"key_pair": schema.StringAttribute{
+				MarkdownDescription: "OpenStack keypair. Defaults to the provider `default_keypair`",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"change_reason": schema.StringAttribute{
+				MarkdownDescription: changeReasonDescription,
+				Optional:            true,
+				WriteOnly:           true,
+			},
+			"wait_for_ready": schema.BoolAttribute{
+				MarkdownDescription: "Set to false to return as soon as the create or update request is accepted instead of waiting for the node pool to be ready. The status is then whatever the API reports right after the request. Defaults to true",
+				Optional:            true,
+			},
+			"stabilize_on_read": schema.BoolAttribute{
+				MarkdownDescription: fmt.Sprintf("Set to true to have refresh wait up to %d seconds for a node pool that is being created or resized to settle, instead of storing the transitional status", stabilizeOnReadAttempts*10),
+				Optional:            true,
+			},
 			// "auto_scale": schema.BoolAttribute{
 			// 	MarkdownDescription: "Node pool auto scale",
 			// 	Optional:            true,
@@ -142,6 +184,10 @@ func (r *NodePoolResource) Schema(ctx context.Context, req resource.SchemaReques
 				MarkdownDescription: "Node pool status",
 				Computed:            true,
 			},
+			"ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether the node pool status is ready",
+				Computed:            true,
+			},
 			"last_error_id": schema.StringAttribute{
 				MarkdownDescription: "Node pool last error id",
 				Computed:            true,
@@ -163,6 +209,18 @@ func (r *NodePoolResource) Schema(ctx context.Context, req resource.SchemaReques
 				Computed:            true,
 				Optional:            true,
 			},
+			"created_at_rfc3339": schema.StringAttribute{
+				MarkdownDescription: "Node pool created at, as an RFC 3339 timestamp",
+				Computed:            true,
+			},
+			"updated_at_rfc3339": schema.StringAttribute{
+				MarkdownDescription: "Node pool updated at, as an RFC 3339 timestamp",
+				Computed:            true,
+			},
+			"deleted_at_rfc3339": schema.StringAttribute{
+				MarkdownDescription: "Node pool deleted at, as an RFC 3339 timestamp",
+				Computed:            true,
+			},
 		},
 	}
 }
@@ -173,18 +231,20 @@ func (r *NodePoolResource) Configure(ctx context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*sdk.ClientWithResponses)
+	data, ok := req.ProviderData.(*stratoResourceData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *sdk.ClientWithResponses, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *stratoResourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.client = data.client
+	r.defaultKeypair = data.defaultKeypair
+	r.polls = data.polls
 }
 
 func (r *NodePoolResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -197,8 +257,20 @@ func (r *NodePoolResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	if data.Name.IsUnknown() || data.Name.IsNull() || data.Name.ValueString() == "" {
-		resp.Diagnostics.AddError("Missing Required Field", "The 'name' field is required")
+	var changeReason types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("change_reason"), &changeReason)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keypair := resolveKeypair(data.KeyPair, r.defaultKeypair)
+	if keypair == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("key_pair"),
+			"Missing keypair",
+			"Set key_pair on the resource or default_keypair on the provider.",
+		)
 		return
 	}
 
@@ -207,7 +279,7 @@ func (r *NodePoolResource) Create(ctx context.Context, req resource.CreateReques
 		Name:       data.Name.ValueString(),
 		FlavorID:   data.FlavorId.ValueString(),
 		NetworkID:  data.NetworkId.ValueString(),
-		Keypair:    data.KeyPair.ValueString(),
+		Keypair:    keypair,
 		VolumeSize: data.VolumeSize.ValueInt64(),
 		NodeCount:  data.NodeCount.ValueInt64(),
 	}
@@ -222,29 +294,85 @@ func (r *NodePoolResource) Create(ctx context.Context, req resource.CreateReques
 	// 	body.MaxNodeCount = &[]int64{data.MaxNodeCount.ValueInt64()}[0]
 	// }
 	// Note: Labels are not supported in CreateNodePoolRequestBody
-
-	createResult, err := r.client.CreateNodepoolWithResponse(ctx, data.ClusterId.ValueString(), &sdk.CreateNodepoolParams{}, body)
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to create node pool", err.Error())
+	// Note: neither is an availability zone, pools are placed by the platform
+	// Note: nor a subnet, servers are attached to network_id and the platform picks the subnet
+	// Note: Keypair is a single keypair name, so extra keypairs cannot be injected into the nodes
+
+	// Node pools of the same cluster created in parallel are serialized by the API, which
+	// rejects the others with a conflict while the cluster is busy: wait for it to settle
+	var createResult *sdk.CreateNodepoolResponse
+	err := retry.Do(
+		func() error {
+			var err error
+			createResult, err = r.client.CreateNodepoolWithResponse(ctx, data.ClusterId.ValueString(), &sdk.CreateNodepoolParams{}, body, changeReasonEditor(changeReason))
+			if err != nil {
+				return err
+			}
+			if isClusterBusy(createResult.StatusCode(), createResult.Body) {
+				return errClusterBusy
+			}
+			return nil
+		},
+		retry.Context(ctx),
+		retry.DelayType(retry.FixedDelay),
+		retry.Delay(pollInterval),
+		retry.Attempts(clusterBusyAttempts),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(func(err error) bool {
+			return errors.Is(err, errClusterBusy)
+		}),
+	)
+	// A conflict that outlasted the wait is reported with its response body below
+	if err != nil && !errors.Is(err, errClusterBusy) {
+		addAPIError(&resp.Diagnostics, "Unable to create node pool", err)
 		return
 	}
 	if createResult.StatusCode() != 200 {
-		resp.Diagnostics.AddError("Unable to create node pool", fmt.Sprintf("http response status code: %d", createResult.StatusCode()))
+		addAPIError(&resp.Diagnostics, "Unable to create node pool", newStatusError(createResult.HTTPResponse, createResult.Body))
 		return
 	}
 	if createResult.JSON200 == nil {
-		resp.Diagnostics.AddError("Unable to create node pool", "node pool is nil")
+		addAPIError(&resp.Diagnostics, "Unable to create node pool", missingBodyError(createResult.HTTPResponse, createResult.Body, errNodePoolNil))
+		return
+	}
+
+	// Track the node pool in state right away so it isn't leaked if the wait below fails
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), createResult.JSON200.Id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_id"), data.ClusterId)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !waitForReady(data.WaitForReady) {
+		if err := r.readNodePool(ctx, data.ClusterId.ValueString(), createResult.JSON200.Id, &data); err != nil {
+			addAPIError(&resp.Diagnostics, "Unable to create node pool", err)
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
 
 	// Wait for node pool to be ready - calculate timeout based on node count (10-20 minutes)
-	attempts := calculateRetryAttempts(data.NodeCount.ValueInt64())
+	attempts := calculateRetryAttempts(data.NodeCount.ValueInt64(), defaultBaseTimeout, defaultLargeClusterIncrement)
+	nodePoolRead := false
+	statuses := newStatusLogger("Node pool", createResult.JSON200.Id)
+	progress := newPollProgress()
 
 	err = retry.Do(
 		func() error {
+			progress.attempt()
+			release, err := r.polls.acquire(ctx)
+			if err != nil {
+				return err
+			}
+			defer release()
 			if err := r.readNodePool(ctx, data.ClusterId.ValueString(), createResult.JSON200.Id, &data); err != nil {
 				return err
 			}
+			nodePoolRead = true
+			statuses.observe(ctx, data.Status.ValueString())
 			switch data.Status.ValueString() {
 			case string(sdk.NODE_POOL_STATUS_CREATING):
 				return fmt.Errorf("node pool is creating")
@@ -262,15 +390,21 @@ func (r *NodePoolResource) Create(ctx context.Context, req resource.CreateReques
 		},
 		retry.Context(ctx),
 		retry.DelayType(retry.FixedDelay),
-		retry.Delay(10*time.Second),
+		retry.Delay(pollInterval),
 		retry.Attempts(attempts),
+		retry.LastErrorOnly(true),
 		retry.RetryIf(func(err error) bool {
 			return err != nil && err.Error() == "node pool is creating"
 		}),
 	)
+	err = progress.timeoutError(err, attempts)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to create node pool", err.Error())
+		// Record everything known about the node pool (Terraform marks it as tainted)
+		if nodePoolRead {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		}
+		addAPIError(&resp.Diagnostics, "Unable to create node pool", err)
 		return
 	}
 
@@ -288,8 +422,35 @@ func (r *NodePoolResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	if err := r.readNodePool(ctx, data.ClusterId.ValueString(), data.Id.ValueString(), &data); err != nil {
-		resp.Diagnostics.AddError("Unable to read node pool", err.Error())
+	attempts := uint(1)
+	if data.StabilizeOnRead.ValueBool() {
+		attempts = stabilizeOnReadAttempts
+	}
+
+	err := retry.Do(
+		func() error {
+			if err := r.readNodePool(ctx, data.ClusterId.ValueString(), data.Id.ValueString(), &data); err != nil {
+				return err
+			}
+			switch data.Status.ValueString() {
+			case string(sdk.NODE_POOL_STATUS_CREATING), string(sdk.NODE_POOL_STATUS_RESIZING):
+				return fmt.Errorf("node pool is in progress")
+			}
+			return nil
+		},
+		retry.Context(ctx),
+		retry.Delay(pollInterval),
+		retry.DelayType(retry.FixedDelay),
+		retry.Attempts(attempts),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(func(err error) bool {
+			return err != nil && err.Error() == "node pool is in progress"
+		}),
+	)
+
+	// A node pool still in progress after the wait is stored as is
+	if err != nil && err.Error() != "node pool is in progress" {
+		addAPIError(&resp.Diagnostics, "Unable to read node pool", err)
 		return
 	}
 
@@ -298,17 +459,32 @@ func (r *NodePoolResource) Read(ctx context.Context, req resource.ReadRequest, r
 }
 
 func (r *NodePoolResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data NodePoolResourceModel
+	var data, state NodePoolResourceModel
 
-	// Read Terraform plan data into the model
+	// Read Terraform plan and prior state data into the models
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var changeReason types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("change_reason"), &changeReason)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	if data.Name.IsUnknown() || data.Name.IsNull() || data.Name.ValueString() == "" {
-		resp.Diagnostics.AddError("Missing Required Field", "The 'name' field is required")
+	// Nothing to send to the API (e.g. only wait_for_ready or stabilize_on_read changed),
+	// just re-read the node pool
+	if data.NodeCount.Equal(state.NodeCount) {
+		if err := r.readNodePool(ctx, data.ClusterId.ValueString(), data.Id.ValueString(), &data); err != nil {
+			addAPIError(&resp.Diagnostics, "Unable to update node pool", err)
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
 
@@ -316,6 +492,12 @@ func (r *NodePoolResource) Update(ctx context.Context, req resource.UpdateReques
 	body := sdk.UpdateNodepoolJSONRequestBody{
 		NodeCount: data.NodeCount.ValueInt64(),
 	}
+	// Note: UpdateNodePoolRequestBody has no rolling update controls, so max_surge and
+	// max_unavailable cannot be passed; the backend decides how the pool is resized
+	// Note: neither has an is-default field, the default pool is the one created with
+	// the cluster, so is_default stays computed and a pool cannot be promoted
+	// Note: nor a scale down strategy, and there is no drain endpoint, so a scale_down_strategy
+	// of drain cannot be honoured; removed nodes are deleted the way the backend sees fit
 
 	// if !data.FlavorId.IsUnknown() && !data.FlavorId.IsNull() {
 	// 	body.FlavorID = &[]string{data.FlavorId.ValueString()}[0]
@@ -334,28 +516,70 @@ func (r *NodePoolResource) Update(ctx context.Context, req resource.UpdateReques
 	// 	body.MaxNodeCount = &[]int64{data.MaxNodeCount.ValueInt64()}[0]
 	// }
 
-	updateResult, err := r.client.UpdateNodepoolWithResponse(ctx, data.ClusterId.ValueString(), data.Id.ValueString(), &sdk.UpdateNodepoolParams{}, body)
-	if err != nil {
-		resp.Diagnostics.AddError("Unable to update node pool", err.Error())
+	// The cluster can be busy with another operation, e.g. on another of its node pools
+	var updateResult *sdk.UpdateNodepoolResponse
+	err := retry.Do(
+		func() error {
+			var err error
+			updateResult, err = r.client.UpdateNodepoolWithResponse(ctx, data.ClusterId.ValueString(), data.Id.ValueString(), &sdk.UpdateNodepoolParams{}, body, changeReasonEditor(changeReason))
+			if err != nil {
+				return err
+			}
+			if isClusterBusy(updateResult.StatusCode(), updateResult.Body) {
+				return errClusterBusy
+			}
+			return nil
+		},
+		retry.Context(ctx),
+		retry.DelayType(retry.FixedDelay),
+		retry.Delay(pollInterval),
+		retry.Attempts(clusterBusyAttempts),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(func(err error) bool {
+			return errors.Is(err, errClusterBusy)
+		}),
+	)
+	// A conflict that outlasted the wait is reported with its response body below
+	if err != nil && !errors.Is(err, errClusterBusy) {
+		addAPIError(&resp.Diagnostics, "Unable to update node pool", err)
 		return
 	}
 	if updateResult.StatusCode() != 200 {
-		resp.Diagnostics.AddError("Unable to update node pool", fmt.Sprintf("http response status code: %d", updateResult.StatusCode()))
+		addAPIError(&resp.Diagnostics, "Unable to update node pool", newStatusError(updateResult.HTTPResponse, updateResult.Body))
 		return
 	}
 	if updateResult.JSON200 == nil {
-		resp.Diagnostics.AddError("Unable to update node pool", "node pool is nil")
+		addAPIError(&resp.Diagnostics, "Unable to update node pool", missingBodyError(updateResult.HTTPResponse, updateResult.Body, errNodePoolNil))
+		return
+	}
+
+	if !waitForReady(data.WaitForReady) {
+		if err := r.readNodePool(ctx, data.ClusterId.ValueString(), data.Id.ValueString(), &data); err != nil {
+			addAPIError(&resp.Diagnostics, "Unable to update node pool", err)
+			return
+		}
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
 
 	// Calculate timeout based on new node count (10-20 minutes)
-	attempts := calculateRetryAttempts(data.NodeCount.ValueInt64())
+	attempts := calculateRetryAttempts(data.NodeCount.ValueInt64(), defaultBaseTimeout, defaultLargeClusterIncrement)
+	statuses := newStatusLogger("Node pool", data.Id.ValueString())
+	progress := newPollProgress()
 
 	err = retry.Do(
 		func() error {
+			progress.attempt()
+			release, err := r.polls.acquire(ctx)
+			if err != nil {
+				return err
+			}
+			defer release()
 			if err := r.readNodePool(ctx, data.ClusterId.ValueString(), data.Id.ValueString(), &data); err != nil {
 				return err
 			}
+			statuses.observe(ctx, data.Status.ValueString())
 			switch data.Status.ValueString() {
 			case string(sdk.NODE_POOL_STATUS_CREATING):
 				return fmt.Errorf("node pool is creating")
@@ -373,20 +597,22 @@ func (r *NodePoolResource) Update(ctx context.Context, req resource.UpdateReques
 		},
 		retry.Context(ctx),
 		retry.DelayType(retry.FixedDelay),
-		retry.Delay(10*time.Second),
+		retry.Delay(pollInterval),
 		retry.Attempts(attempts),
+		retry.LastErrorOnly(true),
 		retry.RetryIf(func(err error) bool {
 			return err != nil && err.Error() == "node pool is resizing"
 		}),
 	)
+	err = progress.timeoutError(err, attempts)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to update node pool", err.Error())
+		addAPIError(&resp.Diagnostics, "Unable to update node pool", err)
 		return
 	}
 
 	if err := r.readNodePool(ctx, data.ClusterId.ValueString(), data.Id.ValueString(), &data); err != nil {
-		resp.Diagnostics.AddError("Unable to update node pool", err.Error())
+		addAPIError(&resp.Diagnostics, "Unable to update node pool", err)
 		return
 	}
 
@@ -404,23 +630,36 @@ func (r *NodePoolResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
+	// Note: the API has no cordon/drain endpoint, so workloads cannot be drained
+	// before the delete request is issued
 	deleteResult, err := r.client.DeleteNodepoolWithResponse(ctx, data.ClusterId.ValueString(), data.Id.ValueString(), &sdk.DeleteNodepoolParams{}, sdk.DeleteNodepoolJSONRequestBody{})
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to delete node pool", err.Error())
+		addAPIError(&resp.Diagnostics, "Unable to delete node pool", err)
 		return
 	}
 	if deleteResult.StatusCode() >= 400 {
-		resp.Diagnostics.AddError("Unable to delete node pool", fmt.Sprintf("http response status code: %d", deleteResult.StatusCode()))
+		addAPIError(&resp.Diagnostics, "Unable to delete node pool", newStatusError(deleteResult.HTTPResponse, deleteResult.Body))
 		return
 	}
 	if deleteResult.JSON200 == nil {
-		resp.Diagnostics.AddError("Unable to delete node pool", "node pool is nil")
+		addAPIError(&resp.Diagnostics, "Unable to delete node pool", missingBodyError(deleteResult.HTTPResponse, deleteResult.Body, errNodePoolNil))
 		return
 	}
 
 	// Wait for node pool to be deleted - use 10 minute timeout (independent of node count)
+	statuses := newStatusLogger("Node pool", data.Id.ValueString())
+	progress := newPollProgress()
 	err = retry.Do(
 		func() error {
+			progress.attempt()
+			release, err := r.polls.acquire(ctx)
+			if err != nil {
+				return err
+			}
+			defer release()
+			ctx, cancel := context.WithTimeout(ctx, pollRequestTimeout)
+			defer cancel()
+
 			showResult, err := r.client.ShowNodePoolWithResponse(ctx, data.ClusterId.ValueString(), data.Id.ValueString(), &sdk.ShowNodePoolParams{})
 			if err != nil {
 				return err
@@ -429,14 +668,15 @@ func (r *NodePoolResource) Delete(ctx context.Context, req resource.DeleteReques
 				return nil
 			}
 			if showResult.StatusCode() != 200 {
-				return fmt.Errorf("http response status code: %d", showResult.StatusCode())
+				return newStatusError(showResult.HTTPResponse, showResult.Body)
 			}
 			if showResult.JSON200 == nil {
-				return fmt.Errorf("node pool is nil")
+				return missingBodyError(showResult.HTTPResponse, showResult.Body, errNodePoolNil)
 			}
 			if showResult.JSON200.Deleted {
 				return nil
 			}
+			statuses.observe(ctx, showResult.JSON200.Status)
 			switch showResult.JSON200.Status {
 			case string(sdk.NODE_POOL_STATUS_CREATING):
 				return fmt.Errorf("node pool is creating")
@@ -447,22 +687,25 @@ func (r *NodePoolResource) Delete(ctx context.Context, req resource.DeleteReques
 			case string(sdk.NODE_POOL_STATUS_DELETING):
 				return fmt.Errorf("node pool is in deleting state")
 			case string(sdk.NODE_POOL_STATUS_READY):
-				return nil
+				// The delete request may not have been picked up yet, or was ignored
+				return fmt.Errorf("node pool is still ready after the delete request")
 			default:
 				return fmt.Errorf("node pool is in unknown state")
 			}
 		},
 		retry.Context(ctx),
 		retry.DelayType(retry.FixedDelay),
-		retry.Delay(10*time.Second),
-		retry.Attempts(60), // 10 minutes
+		retry.Delay(pollInterval),
+		retry.Attempts(deletePollAttempts),
+		retry.LastErrorOnly(true),
 		retry.RetryIf(func(err error) bool {
-			return err != nil && err.Error() == "node pool is in deleting state"
+			return err != nil && (err.Error() == "node pool is in deleting state" || err.Error() == "node pool is still ready after the delete request")
 		}),
 	)
+	err = progress.timeoutError(err, deletePollAttempts)
 
 	if err != nil {
-		resp.Diagnostics.AddError("Unable to delete node pool", err.Error())
+		addAPIError(&resp.Diagnostics, "Unable to delete node pool", err)
 		return
 	}
 }
@@ -471,17 +714,31 @@ func (r *NodePoolResource) ImportState(ctx context.Context, req resource.ImportS
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+// clusterBusyAttempts bounds how long creates and updates wait for a busy cluster (5 minutes).
+const clusterBusyAttempts = 30
+
+// trimmedNameRegexp matches names without leading or trailing whitespace, which
+// the API would otherwise turn into dashes when normalizing the name.
+var trimmedNameRegexp = regexp.MustCompile(`^\S(.*\S)?$`)
+
+// nameCharacterRegexp matches names with at least one character kept by the API's
+// normalization, so that the normalized name is not empty.
+var nameCharacterRegexp = regexp.MustCompile(`[A-Za-z0-9]`)
+
 func (r *NodePoolResource) readNodePool(ctx context.Context, clusterId, nodePoolId string, data *NodePoolResourceModel) error {
+	ctx, cancel := context.WithTimeout(ctx, pollRequestTimeout)
+	defer cancel()
+
 	params := &sdk.ShowNodePoolParams{}
 	result, err := r.client.ShowNodePoolWithResponse(ctx, clusterId, nodePoolId, params)
 	if err != nil {
 		return err
 	}
 	if result.StatusCode() != 200 {
-		return fmt.Errorf("http response status code: %d", result.StatusCode())
+		return newStatusError(result.HTTPResponse, result.Body)
 	}
 	if result.JSON200 == nil {
-		return fmt.Errorf("node pool is nil")
+		return missingBodyError(result.HTTPResponse, result.Body, errNodePoolNil)
 	}
 
 	nodePool := result.JSON200
@@ -495,13 +752,17 @@ func (r *NodePoolResource) readNodePool(ctx context.Context, clusterId, nodePool
 	data.VolumeSize = types.Int64Value(nodePool.VolumeSize)
 	data.IsDefault = types.BoolValue(nodePool.IsDefault)
 	data.NodeCount = types.Int64Value(nodePool.NodeCount)
+	// Note: the API has no endpoint listing node pool members, so per-node details
+	// (id, name, ip, status) cannot be exposed here
 
 	// data.MaxNodeCount = types.Int64Value(nodePool.MaxNodeCount)
 	// data.MinNodeCount = types.Int64Value(nodePool.MinNodeCount)
 	// data.AutoScale = types.BoolValue(nodePool.AutoScale)
 
 	data.Status = types.StringValue(nodePool.Status)
+	data.Ready = types.BoolValue(nodePool.Status == string(sdk.NODE_POOL_STATUS_READY))
 	data.LastErrorId = types.StringValue(nodePool.LastErrorID)
+	// Note: the API has no endpoint resolving an error id, so there is no last_error_message
 	data.CreatedAt = types.Int64Value(nodePool.CreatedAt)
 	data.UpdatedAt = types.Int64Value(nodePool.UpdatedAt)
 	data.Deleted = types.BoolValue(nodePool.Deleted)
@@ -511,6 +772,12 @@ func (r *NodePoolResource) readNodePool(ctx context.Context, clusterId, nodePool
 	} else {
 		data.DeletedAt = types.Int64Null()
 	}
+	data.CreatedAtRFC3339 = epochToRFC3339(nodePool.CreatedAt)
+	data.UpdatedAtRFC3339 = epochToRFC3339(nodePool.UpdatedAt)
+	data.DeletedAtRFC3339 = types.StringNull()
+	if nodePool.DeletedAt != nil {
+		data.DeletedAtRFC3339 = epochToRFC3339(*nodePool.DeletedAt)
+	}
 
 	return nil
 }
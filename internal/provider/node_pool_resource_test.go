@@ -0,0 +1,337 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/QumulusTechnology/strato-project/sdk"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func testNodePoolModel(nodeCount int64) NodePoolResourceModel {
+	return NodePoolResourceModel{
+		ClusterId:  types.StringValue("cluster"),
+		Name:       types.StringValue("workers"),
+		FlavorId:   types.StringValue("flavor"),
+		NetworkId:  types.StringValue("network"),
+		KeyPair:    types.StringValue("keypair"),
+		VolumeSize: types.Int64Value(20),
+		NodeCount:  types.Int64Value(nodeCount),
+	}
+}
+
+// testCreateNodePool creates a node pool on the mock server and returns its state.
+func testCreateNodePool(t *testing.T, r *NodePoolResource, model NodePoolResourceModel) (tfsdk.State, diag.Diagnostics) {
+	t.Helper()
+
+	s := testResourceSchema(t, r)
+	plan, config := testPlan(t, s, &model)
+	resp := resource.CreateResponse{State: testState(t, s, nil)}
+	r.Create(context.Background(), resource.CreateRequest{Plan: plan, Config: config}, &resp)
+
+	return resp.State, resp.Diagnostics
+}
+
+func TestNodePoolNameRegexps(t *testing.T) {
+	cases := map[string]struct {
+		name     string
+		expected bool
+	}{
+		"plain":               {name: "workers", expected: true},
+		"normalized":          {name: "GPU Workers", expected: true},
+		"empty":               {name: ""},
+		"whitespace":          {name: "   "},
+		"leading whitespace":  {name: " workers"},
+		"trailing whitespace": {name: "workers\t"},
+		"only separators":     {name: "--_"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := trimmedNameRegexp.MatchString(tc.name) && nameCharacterRegexp.MatchString(tc.name); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNodePoolResourceCreate(t *testing.T) {
+	cases := map[string]struct {
+		settleAfter        int
+		conflictingCreates int
+		skipWait           bool
+		nodePoolStatus     sdk.NodePoolStatus
+		expectError        bool
+		expectedStatus     sdk.NodePoolStatus
+	}{
+		"ready": {
+			settleAfter:    2,
+			nodePoolStatus: sdk.NODE_POOL_STATUS_READY,
+			expectedStatus: sdk.NODE_POOL_STATUS_READY,
+		},
+		"without waiting": {
+			settleAfter:    2,
+			skipWait:       true,
+			nodePoolStatus: sdk.NODE_POOL_STATUS_READY,
+			expectedStatus: sdk.NODE_POOL_STATUS_CREATING,
+		},
+		"cluster busy": {
+			settleAfter:        2,
+			conflictingCreates: 2,
+			nodePoolStatus:     sdk.NODE_POOL_STATUS_READY,
+			expectedStatus:     sdk.NODE_POOL_STATUS_READY,
+		},
+		"error state": {
+			settleAfter:    2,
+			nodePoolStatus: sdk.NODE_POOL_STATUS_ERROR,
+			expectError:    true,
+			expectedStatus: sdk.NODE_POOL_STATUS_ERROR,
+		},
+		"timeout": {
+			settleAfter:    1000,
+			nodePoolStatus: sdk.NODE_POOL_STATUS_READY,
+			expectError:    true,
+			expectedStatus: sdk.NODE_POOL_STATUS_CREATING,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := newMockStratoServer(t)
+			server.addCluster("cluster")
+			server.settleAfter = tc.settleAfter
+			server.nodePoolStatus = string(tc.nodePoolStatus)
+			server.conflictingCreates = tc.conflictingCreates
+			r := &NodePoolResource{client: server.client(t)}
+
+			model := testNodePoolModel(1)
+			model.WaitForReady = types.BoolValue(!tc.skipWait)
+			state, diags := testCreateNodePool(t, r, model)
+
+			if diags.HasError() != tc.expectError {
+				t.Fatalf("expected error %t, got diagnostics: %v", tc.expectError, diags)
+			}
+
+			// The node pool must be tracked in state even when it never became ready
+			var data NodePoolResourceModel
+			if diags := state.Get(context.Background(), &data); diags.HasError() {
+				t.Fatalf("unexpected state diagnostics: %v", diags)
+			}
+			if data.Id.ValueString() == "" {
+				t.Error("expected node pool id to be saved in state")
+			}
+			if data.Status.ValueString() != string(tc.expectedStatus) {
+				t.Errorf("expected status %q, got %q", tc.expectedStatus, data.Status.ValueString())
+			}
+		})
+	}
+}
+
+func TestNodePoolResourceCreateDefaultKeypair(t *testing.T) {
+	cases := map[string]struct {
+		keyPair        types.String
+		defaultKeypair string
+		expectError    bool
+		expected       string
+	}{
+		"resource value": {
+			keyPair:        types.StringValue("keypair"),
+			defaultKeypair: "default",
+			expected:       "keypair",
+		},
+		"provider default": {
+			keyPair:        types.StringNull(),
+			defaultKeypair: "default",
+			expected:       "default",
+		},
+		"missing": {
+			keyPair:     types.StringNull(),
+			expectError: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := newMockStratoServer(t)
+			server.addCluster("cluster")
+			r := &NodePoolResource{client: server.client(t), defaultKeypair: tc.defaultKeypair}
+
+			model := testNodePoolModel(1)
+			model.KeyPair = tc.keyPair
+			state, diags := testCreateNodePool(t, r, model)
+
+			if diags.HasError() != tc.expectError {
+				t.Fatalf("expected error %t, got diagnostics: %v", tc.expectError, diags)
+			}
+			if tc.expectError {
+				return
+			}
+
+			var data NodePoolResourceModel
+			state.Get(context.Background(), &data)
+			if data.KeyPair.ValueString() != tc.expected {
+				t.Errorf("expected key pair %q, got %q", tc.expected, data.KeyPair.ValueString())
+			}
+		})
+	}
+}
+
+func TestNodePoolResourceRead(t *testing.T) {
+	cases := map[string]struct {
+		stabilizeOnRead bool
+		expectedStatus  sdk.NodePoolStatus
+	}{
+		"transitional status stored": {
+			expectedStatus: sdk.NODE_POOL_STATUS_CREATING,
+		},
+		"stabilize on read": {
+			stabilizeOnRead: true,
+			expectedStatus:  sdk.NODE_POOL_STATUS_READY,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := newMockStratoServer(t)
+			server.addCluster("cluster")
+			r := &NodePoolResource{client: server.client(t)}
+
+			model := testNodePoolModel(1)
+			model.WaitForReady = types.BoolValue(false)
+			model.StabilizeOnRead = types.BoolValue(tc.stabilizeOnRead)
+			state, diags := testCreateNodePool(t, r, model)
+			if diags.HasError() {
+				t.Fatalf("unexpected create diagnostics: %v", diags)
+			}
+
+			resp := resource.ReadResponse{State: state}
+			r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected read diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data NodePoolResourceModel
+			resp.State.Get(context.Background(), &data)
+			if data.Status.ValueString() != string(tc.expectedStatus) {
+				t.Errorf("expected status %q, got %q", tc.expectedStatus, data.Status.ValueString())
+			}
+		})
+	}
+}
+
+func TestNodePoolResourceUpdate(t *testing.T) {
+	cases := map[string]struct {
+		nodeCount          int64
+		stabilizeOnRead    bool
+		nodePoolStatus     sdk.NodePoolStatus
+		conflictingUpdates int
+		expectedPuts       int
+		expectError        bool
+	}{
+		"unchanged node count": {
+			nodeCount:       1,
+			stabilizeOnRead: true,
+			nodePoolStatus:  sdk.NODE_POOL_STATUS_READY,
+		},
+		"resize": {
+			nodeCount:      3,
+			nodePoolStatus: sdk.NODE_POOL_STATUS_READY,
+			expectedPuts:   1,
+		},
+		"resize error": {
+			nodeCount:      3,
+			nodePoolStatus: sdk.NODE_POOL_STATUS_ERROR,
+			expectedPuts:   1,
+			expectError:    true,
+		},
+		"cluster busy": {
+			nodeCount:          3,
+			nodePoolStatus:     sdk.NODE_POOL_STATUS_READY,
+			conflictingUpdates: 2,
+			expectedPuts:       3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := newMockStratoServer(t)
+			server.addCluster("cluster")
+			r := &NodePoolResource{client: server.client(t)}
+			s := testResourceSchema(t, r)
+
+			state, diags := testCreateNodePool(t, r, testNodePoolModel(1))
+			if diags.HasError() {
+				t.Fatalf("unexpected create diagnostics: %v", diags)
+			}
+			var data NodePoolResourceModel
+			state.Get(context.Background(), &data)
+
+			server.nodePoolStatus = string(tc.nodePoolStatus)
+			server.conflictingUpdates = tc.conflictingUpdates
+			data.NodeCount = types.Int64Value(tc.nodeCount)
+			data.StabilizeOnRead = types.BoolValue(tc.stabilizeOnRead)
+			plan, config := testPlan(t, s, &data)
+			resp := resource.UpdateResponse{State: state}
+			r.Update(context.Background(), resource.UpdateRequest{Plan: plan, Config: config, State: state}, &resp)
+
+			if resp.Diagnostics.HasError() != tc.expectError {
+				t.Fatalf("expected error %t, got diagnostics: %v", tc.expectError, resp.Diagnostics)
+			}
+			if puts := server.requestCount(http.MethodPut); puts != tc.expectedPuts {
+				t.Errorf("expected %d update requests, got %d", tc.expectedPuts, puts)
+			}
+			if tc.expectError {
+				return
+			}
+
+			resp.State.Get(context.Background(), &data)
+			if data.NodeCount.ValueInt64() != tc.nodeCount {
+				t.Errorf("expected node count %d, got %d", tc.nodeCount, data.NodeCount.ValueInt64())
+			}
+		})
+	}
+}
+
+func TestNodePoolResourceDelete(t *testing.T) {
+	cases := map[string]struct {
+		ignoreDeletes bool
+		expectError   bool
+	}{
+		"wait for deletion": {},
+		"delete ignored": {
+			ignoreDeletes: true,
+			expectError:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := newMockStratoServer(t)
+			server.addCluster("cluster")
+			r := &NodePoolResource{client: server.client(t)}
+
+			state, diags := testCreateNodePool(t, r, testNodePoolModel(1))
+			if diags.HasError() {
+				t.Fatalf("unexpected create diagnostics: %v", diags)
+			}
+
+			server.ignoreDeletes = tc.ignoreDeletes
+			resp := resource.DeleteResponse{State: state}
+			r.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+
+			if resp.Diagnostics.HasError() != tc.expectError {
+				t.Fatalf("expected error %t, got diagnostics: %v", tc.expectError, resp.Diagnostics)
+			}
+			if deleted := len(server.nodePools) == 0; deleted == tc.expectError {
+				t.Errorf("expected node pool deleted %t, got %t", !tc.expectError, deleted)
+			}
+		})
+	}
+}
@@ -6,12 +6,15 @@ package provider
 import (
 	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/QumulusTechnology/strato-project/sdk"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
@@ -19,14 +22,30 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
 )
 
 // Ensure stratoProvider satisfies various provider interfaces.
 var _ provider.Provider = &stratoProvider{}
 var _ provider.ProviderWithFunctions = &stratoProvider{}
 var _ provider.ProviderWithEphemeralResources = &stratoProvider{}
+var _ provider.ProviderWithConfigValidators = &stratoProvider{}
+
+// defaultEndpoint is the Strato API base URL used when the endpoint attribute is unset.
+const defaultEndpoint = "https://api.cloudportal.run/strato/"
+
+// authMethodsDescription documents the mutually exclusive authentication attributes.
+const authMethodsDescription = "Exactly one of `bearer_token`, `token_command` or `application_credential_id` must be set"
+
+// Authentication methods, as reported by the strato_provider_config data source.
+const (
+	authMethodBearerToken           = "bearer_token"
+	authMethodTokenCommand          = "token_command"
+	authMethodApplicationCredential = "application_credential"
+)
 
 // stratoProvider defines the provider implementation.
 type stratoProvider struct {
@@ -38,7 +57,45 @@ type stratoProvider struct {
 
 // stratoProviderModel describes the provider data model.
 type stratoProviderModel struct {
-	BearerToken types.String `tfsdk:"bearer_token"`
+	Endpoint             types.String `tfsdk:"endpoint"`
+	BearerToken          types.String `tfsdk:"bearer_token"`
+	TokenCommand         types.List   `tfsdk:"token_command"`
+	AuthURL              types.String `tfsdk:"auth_url"`
+	AppCredentialId      types.String `tfsdk:"application_credential_id"`
+	AppCredentialSecret  types.String `tfsdk:"application_credential_secret"`
+	MaxRetries           types.Int64  `tfsdk:"max_retries"`
+	Debug                types.Bool   `tfsdk:"debug"`
+	UserAgentSuffix      types.String `tfsdk:"user_agent_suffix"`
+	MaxRequestsPerSecond types.Int64  `tfsdk:"max_requests_per_second"`
+	MaxConcurrentPolls   types.Int64  `tfsdk:"max_concurrent_polls"`
+	ExtraHeaders         types.Map    `tfsdk:"extra_headers"`
+	DefaultKeypair       types.String `tfsdk:"default_keypair"`
+}
+
+// stratoDataSourceData is handed to data sources on Configure: the API client along
+// with the non-sensitive configuration the provider resolved.
+type stratoDataSourceData struct {
+	client *sdk.ClientWithResponses
+	config resolvedProviderConfig
+}
+
+// resolvedProviderConfig is the provider configuration after defaults are applied,
+// without any secret.
+type resolvedProviderConfig struct {
+	endpoint     string
+	authMethod   string
+	tokenPresent bool
+	version      string
+	userAgent    string
+}
+
+// stratoResourceData is handed to resources and ephemeral resources on Configure:
+// the API client along with the provider level defaults resources fall back to and
+// the semaphore their status checks share.
+type stratoResourceData struct {
+	client         *sdk.ClientWithResponses
+	defaultKeypair string
+	polls          pollSemaphore
 }
 
 func (p *stratoProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -49,15 +106,94 @@ func (p *stratoProvider) Metadata(ctx context.Context, req provider.MetadataRequ
 func (p *stratoProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
+			"endpoint": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Base URL of the Strato API. Defaults to `%s`", defaultEndpoint),
+				Optional:            true,
+			},
 			"bearer_token": schema.StringAttribute{
-				MarkdownDescription: "Bearer token for the Strato API",
-				Required:            true,
+				MarkdownDescription: "Bearer token for the Strato API, without the `Bearer` prefix. JWTs are checked not to have expired. " + authMethodsDescription,
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"token_command": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Command, as a program followed by its arguments, that prints a bearer token for the Strato API on stdout. It is run again whenever the token is about to expire (based on its `exp` claim, otherwise every 5 minutes) so that long running applies outlive short lived tokens. " + authMethodsDescription,
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"auth_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the OpenStack identity service (Keystone v3) the application credential is exchanged with, e.g. `https://keystone.example.com:5000/v3`. Required with `application_credential_id`",
+				Optional:            true,
+			},
+			"application_credential_id": schema.StringAttribute{
+				MarkdownDescription: "OpenStack application credential id, exchanged for a token at `auth_url` and again whenever the token is about to expire. " + authMethodsDescription,
+				Optional:            true,
+			},
+			"application_credential_secret": schema.StringAttribute{
+				MarkdownDescription: "OpenStack application credential secret. Required with `application_credential_id`",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of times an idempotent request is retried when the Strato API answers with HTTP 429, 502, 503 or 504. Defaults to %d", defaultMaxRetries),
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"debug": schema.BoolAttribute{
+				MarkdownDescription: "Set to true to log the details of every HTTP request sent to and response received from the Strato API at debug level (`TF_LOG=DEBUG`). Defaults to false, in which case requests and responses are not inspected at all, so their bodies are never buffered for logging",
+				Optional:            true,
+			},
+			"max_requests_per_second": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of requests per second sent to the Strato API, shared by all resources and data sources of this provider. Unlimited by default",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_concurrent_polls": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of status checks in flight at once while resources wait for clusters and node pools to be created, resized or deleted, shared by all resources of this provider and the `strato_cluster_status` ephemeral resource. Unlimited by default",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"extra_headers": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Additional HTTP headers sent with every request to the Strato API, e.g. for routing through an API gateway. Headers set by the provider itself, such as `Authorization`, take precedence. Their values are redacted from debug logs",
+				Optional:            true,
 				Sensitive:           true,
 			},
+			"default_keypair": schema.StringAttribute{
+				MarkdownDescription: "OpenStack keypair used by clusters and node pools that do not set their own `keypair` or `key_pair`",
+				Optional:            true,
+			},
+			"user_agent_suffix": schema.StringAttribute{
+				MarkdownDescription: "Text appended to the `User-Agent` header sent to the Strato API, e.g. a team or pipeline identifier. The header always starts with `terraform-provider-strato/<version>`",
+				Optional:            true,
+			},
 		},
 	}
 }
 
+func (p *stratoProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		providervalidator.ExactlyOneOf(
+			path.MatchRoot("bearer_token"),
+			path.MatchRoot("token_command"),
+			path.MatchRoot("application_credential_id"),
+		),
+		providervalidator.RequiredTogether(
+			path.MatchRoot("application_credential_id"),
+			path.MatchRoot("application_credential_secret"),
+			path.MatchRoot("auth_url"),
+		),
+	}
+}
+
 func (p *stratoProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var data stratoProviderModel
 
@@ -67,6 +203,14 @@ func (p *stratoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
+	if data.Endpoint.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Unknown endpoint",
+			"The provider cannot create the Strato API client as there is an unknown configuration value for the endpoint.",
+		)
+	}
+
 	if data.BearerToken.IsUnknown() {
 		resp.Diagnostics.AddAttributeError(
 			path.Root("bearer_token"),
@@ -75,10 +219,84 @@ func (p *stratoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		)
 	}
 
+	if data.TokenCommand.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token_command"),
+			"Unknown token command",
+			"The provider cannot create the Strato API client as there is an unknown configuration value for the token command.",
+		)
+	}
+
+	if data.AuthURL.IsUnknown() || data.AppCredentialId.IsUnknown() || data.AppCredentialSecret.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("application_credential_id"),
+			"Unknown application credential",
+			"The provider cannot create the Strato API client as there is an unknown configuration value for the application credential or the auth URL.",
+		)
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if !data.BearerToken.IsNull() {
+		if msg := bearerTokenError(data.BearerToken.ValueString(), time.Now()); msg != "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("bearer_token"),
+				"Invalid bearer token",
+				"The provider cannot create the Strato API client as the bearer token is not usable. "+msg,
+			)
+			return
+		}
+	}
+
+	tokens := newStaticTokenSource(data.BearerToken.ValueString())
+	authMethod := authMethodBearerToken
+	if !data.TokenCommand.IsNull() {
+		var command []string
+		resp.Diagnostics.Append(data.TokenCommand.ElementsAs(ctx, &command, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		tokens = newCommandTokenSource(command)
+		authMethod = authMethodTokenCommand
+
+		// Fail early rather than on the first API request
+		if _, err := tokens.Token(ctx); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("token_command"),
+				"Unable to fetch bearer token",
+				"The provider cannot create the Strato API client as the token command failed: "+err.Error(),
+			)
+			return
+		}
+	}
+	if !data.AppCredentialId.IsNull() {
+		tokens = newApplicationCredentialTokenSource(
+			data.AuthURL.ValueString(),
+			data.AppCredentialId.ValueString(),
+			data.AppCredentialSecret.ValueString(),
+		)
+		authMethod = authMethodApplicationCredential
+
+		// Fail early rather than on the first API request
+		if _, err := tokens.Token(ctx); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("application_credential_id"),
+				"Unable to fetch bearer token",
+				"The provider cannot create the Strato API client as the application credential could not be exchanged for a token: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	var extraHeaders map[string]string
+	if !data.ExtraHeaders.IsNull() && !data.ExtraHeaders.IsUnknown() {
+		resp.Diagnostics.Append(data.ExtraHeaders.ElementsAs(ctx, &extraHeaders, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 	debugOption := sdk.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
 		var msg strings.Builder
 		msg.WriteString("HTTP Request:\n")
@@ -116,11 +334,11 @@ func (p *stratoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 			}
 		}
 
-		// Log ALL headers for debugging
+		// Log ALL headers for debugging, extra headers may carry gateway credentials
 		msg.WriteString("  Headers:\n")
 		for name, values := range req.Header {
 			for _, value := range values {
-				if name == "Authorization" {
+				if strings.EqualFold(name, "Authorization") || isExtraHeader(extraHeaders, name) {
 					msg.WriteString(fmt.Sprintf("    %s: [REDACTED]\n", name))
 				} else {
 					msg.WriteString(fmt.Sprintf("    %s: %s\n", name, value))
@@ -129,16 +347,11 @@ func (p *stratoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		}
 
 		if req.Body != nil && req.ContentLength != 0 {
-			body, err := io.ReadAll(req.Body)
-			if err == nil && len(body) > 0 {
-				// Reset the body for subsequent reads
-				req.Body = io.NopCloser(strings.NewReader(string(body)))
-
-				bodyStr := string(body)
-				if len(bodyStr) > 1000 {
-					bodyStr = bodyStr[:1000] + "... [truncated]"
-				}
-				msg.WriteString("  Body: " + bodyStr + "\n")
+			// Only read what may be logged, the body is replayed in full when sent
+			prefix, body, err := peekBody(req.Body, maxLoggedBodyLength)
+			req.Body = body
+			if err == nil && len(prefix) > 0 {
+				msg.WriteString("  Body: " + redactBodyPrefix(prefix, maxLoggedBodyLength) + "\n")
 			}
 		}
 
@@ -147,10 +360,60 @@ func (p *stratoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return nil
 	})
 	authClientOption := sdk.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
-		req.Header.Set("Authorization", "Bearer "+data.BearerToken.ValueString())
+		token, err := tokens.Token(ctx)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
 		return nil
 	})
-	client, err := sdk.NewClientWithResponses("https://api.cloudportal.run/strato/", authClientOption, debugOption)
+	userAgent := "terraform-provider-strato/" + p.version
+	if suffix := strings.TrimSpace(data.UserAgentSuffix.ValueString()); suffix != "" {
+		userAgent += " " + suffix
+	}
+	userAgentOption := sdk.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("User-Agent", userAgent)
+		return nil
+	})
+	extraHeadersOption := sdk.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+		for name, value := range extraHeaders {
+			if req.Header.Get(name) == "" {
+				req.Header.Set(name, value)
+			}
+		}
+		return nil
+	})
+	maxRetries := int64(defaultMaxRetries)
+	if !data.MaxRetries.IsNull() && !data.MaxRetries.IsUnknown() {
+		maxRetries = data.MaxRetries.ValueInt64()
+	}
+	transport := http.DefaultTransport
+	if data.Debug.ValueBool() {
+		transport = &loggingTransport{next: transport}
+	}
+	if !data.MaxRequestsPerSecond.IsNull() && !data.MaxRequestsPerSecond.IsUnknown() {
+		limit := data.MaxRequestsPerSecond.ValueInt64()
+		transport = &rateLimitTransport{
+			next:    transport,
+			limiter: rate.NewLimiter(rate.Limit(limit), int(limit)),
+		}
+	}
+	httpClient := &http.Client{
+		Transport: &retryTransport{
+			next:       transport,
+			maxRetries: int(maxRetries),
+		},
+	}
+	clientOptions := []sdk.ClientOption{sdk.WithHTTPClient(httpClient), authClientOption, userAgentOption, extraHeadersOption}
+	// The debug editor buffers request bodies, so it is only registered when asked for
+	if data.Debug.ValueBool() {
+		clientOptions = append(clientOptions, debugOption)
+	}
+	endpoint := defaultEndpoint
+	if data.Endpoint.ValueString() != "" {
+		endpoint = data.Endpoint.ValueString()
+	}
+	client, err := sdk.NewClientWithResponses(endpoint, clientOptions...)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to create Strato client",
@@ -159,8 +422,25 @@ func (p *stratoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	// Token sources that fetch tokens already did so above, this does not hit the network
+	token, err := tokens.Token(ctx)
+	resp.DataSourceData = &stratoDataSourceData{
+		client: client,
+		config: resolvedProviderConfig{
+			endpoint:     endpoint,
+			authMethod:   authMethod,
+			tokenPresent: err == nil && token != "",
+			version:      p.version,
+			userAgent:    userAgent,
+		},
+	}
+	resourceData := &stratoResourceData{
+		client:         client,
+		defaultKeypair: data.DefaultKeypair.ValueString(),
+		polls:          newPollSemaphore(data.MaxConcurrentPolls.ValueInt64()),
+	}
+	resp.EphemeralResourceData = resourceData
+	resp.ResourceData = resourceData
 }
 
 func (p *stratoProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -171,24 +451,33 @@ func (p *stratoProvider) Resources(ctx context.Context) []func() resource.Resour
 }
 
 func (p *stratoProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
-	return nil
-	// return []func() ephemeral.EphemeralResource{
-	// 	NewExampleEphemeralResource,
-	// }
+	// Note: a strato_kubeconfig ephemeral resource needs a kubeconfig endpoint,
+	// which the Strato API does not expose yet
+	return []func() ephemeral.EphemeralResource{
+		NewClusterStatusEphemeralResource,
+	}
 }
 
 func (p *stratoProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	// Note: a strato_flavors data source needs a flavor listing endpoint, which the
+	// Strato API does not expose yet
+	// Note: a strato_cluster_events data source needs a cluster events endpoint, the
+	// Strato API only reports the last error id of a cluster
+	// Note: a strato_kubernetes_versions data source needs a version listing endpoint,
+	// the Strato API neither lists Kubernetes versions nor lets clusters pick one
 	return []func() datasource.DataSource{
 		NewClusterDataSource,
 		NewNodePoolDataSource,
+		NewProviderConfigDataSource,
 	}
 }
 
 func (p *stratoProvider) Functions(ctx context.Context) []func() function.Function {
-	return nil
-	// return []func() function.Function{
-	// 	NewExampleFunction,
-	// }
+	// Note: a node_pool_full_name function cannot be offered, the full name of a node pool
+	// carries a suffix generated by the API when the pool is created, so it cannot be predicted
+	return []func() function.Function{
+		NewKubeconfigFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
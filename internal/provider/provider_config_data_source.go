@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ProviderConfigDataSource{}
+
+func NewProviderConfigDataSource() datasource.DataSource {
+	return &ProviderConfigDataSource{}
+}
+
+// ProviderConfigDataSource reports the configuration the provider resolved, so that
+// the backend a workspace talks to can be checked without debug logging. Secrets are
+// never part of it.
+type ProviderConfigDataSource struct {
+	config resolvedProviderConfig
+}
+
+// ProviderConfigDataSourceModel describes the data source data model.
+type ProviderConfigDataSourceModel struct {
+	Endpoint        types.String `tfsdk:"endpoint"`
+	AuthMethod      types.String `tfsdk:"auth_method"`
+	TokenPresent    types.Bool   `tfsdk:"token_present"`
+	ProviderVersion types.String `tfsdk:"provider_version"`
+	UserAgent       types.String `tfsdk:"user_agent"`
+}
+
+func (d *ProviderConfigDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_provider_config"
+}
+
+func (d *ProviderConfigDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Non-sensitive configuration resolved by the provider, to check which Strato API a workspace is talking to",
+
+		Attributes: map[string]schema.Attribute{
+			"endpoint": schema.StringAttribute{
+				MarkdownDescription: "Base URL of the Strato API requests are sent to, after defaults are applied",
+				Computed:            true,
+			},
+			"auth_method": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Authentication method in use, one of `%s`, `%s` or `%s`", authMethodBearerToken, authMethodTokenCommand, authMethodApplicationCredential),
+				Computed:            true,
+			},
+			"token_present": schema.BoolAttribute{
+				MarkdownDescription: "Whether the provider holds a non-empty bearer token. The token itself is never reported",
+				Computed:            true,
+			},
+			"provider_version": schema.StringAttribute{
+				MarkdownDescription: "Version of the provider",
+				Computed:            true,
+			},
+			"user_agent": schema.StringAttribute{
+				MarkdownDescription: "`User-Agent` header sent to the Strato API",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *ProviderConfigDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*stratoDataSourceData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *stratoDataSourceData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.config = data.config
+}
+
+func (d *ProviderConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Note: the Strato API does not report its version, so there is no api_version to expose
+	data := ProviderConfigDataSourceModel{
+		Endpoint:        types.StringValue(d.config.endpoint),
+		AuthMethod:      types.StringValue(d.config.authMethod),
+		TokenPresent:    types.BoolValue(d.config.tokenPresent),
+		ProviderVersion: types.StringValue(d.config.version),
+		UserAgent:       types.StringValue(d.config.userAgent),
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -3,14 +3,137 @@
 
 package provider
 
-// This file is intentionally empty but kept for future test implementations.
-// When adding acceptance tests for resources and data sources, use the following pattern:
-//
-// import (
-//     "github.com/hashicorp/terraform-plugin-framework/providerserver"
-//     "github.com/hashicorp/terraform-plugin-go/tfprotov6"
-// )
-//
-// var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-//     "strato": providerserver.NewProtocol6WithError(New("test")()),
-// }
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// testAccProtoV6ProviderFactories are used to instantiate a provider during
+// acceptance testing. The factory function will be invoked for every Terraform
+// CLI command executed to create a provider server to which the CLI can
+// reattach.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"strato": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// testAccProviderConfig configures the provider against the mock server.
+func testAccProviderConfig(server *mockStratoServer) string {
+	return fmt.Sprintf(`
+provider "strato" {
+  endpoint     = %q
+  bearer_token = "test"
+}
+`, server.URL+"/")
+}
+
+func TestAccClusterResource(t *testing.T) {
+	server := newMockStratoServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + testAccClusterResourceConfig(1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("strato_cluster.test", "id"),
+					resource.TestCheckResourceAttr("strato_cluster.test", "status", "ready"),
+					resource.TestCheckResourceAttr("strato_cluster.test", "ready", "true"),
+				),
+			},
+			{
+				ResourceName:      "strato_cluster.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccProviderConfig(server) + testAccClusterResourceConfig(2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("strato_cluster.test", "node_count", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccClusterResourceConfig(nodeCount int) string {
+	return fmt.Sprintf(`
+resource "strato_cluster" "test" {
+  cluster_id  = "openstack-cluster"
+  project_id  = "openstack-project"
+  name        = "test"
+  keypair     = "keypair"
+  network_id  = "8a1c5a0e-3f0b-4c8e-9d2a-6b7e1f0c9d41"
+  flavor_id   = "flavor"
+  volume_size = 20
+  node_count  = %d
+}
+`, nodeCount)
+}
+
+func TestAccProviderConfigDataSource(t *testing.T) {
+	server := newMockStratoServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProviderConfig(server) + `data "strato_provider_config" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.strato_provider_config.test", "endpoint", server.URL+"/"),
+					resource.TestCheckResourceAttr("data.strato_provider_config.test", "auth_method", authMethodBearerToken),
+					resource.TestCheckResourceAttr("data.strato_provider_config.test", "token_present", "true"),
+					resource.TestCheckResourceAttr("data.strato_provider_config.test", "provider_version", "test"),
+				),
+			},
+		},
+	})
+}
+
+// testResourceSchema returns the schema of r.
+func testResourceSchema(t *testing.T, r fwresource.Resource) schema.Schema {
+	t.Helper()
+
+	var resp fwresource.SchemaResponse
+	r.Schema(context.Background(), fwresource.SchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected schema diagnostics: %v", resp.Diagnostics)
+	}
+
+	return resp.Schema
+}
+
+// testPlan returns a plan holding model, along with a config holding the same values.
+func testPlan(t *testing.T, s schema.Schema, model any) (tfsdk.Plan, tfsdk.Config) {
+	t.Helper()
+
+	plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+	if diags := plan.Set(context.Background(), model); diags.HasError() {
+		t.Fatalf("unexpected plan diagnostics: %v", diags)
+	}
+
+	return plan, tfsdk.Config{Schema: s, Raw: plan.Raw}
+}
+
+// testState returns a state holding model, or an empty state when model is nil.
+func testState(t *testing.T, s schema.Schema, model any) tfsdk.State {
+	t.Helper()
+
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+	if model == nil {
+		return state
+	}
+	if diags := state.Set(context.Background(), model); diags.HasError() {
+		t.Fatalf("unexpected state diagnostics: %v", diags)
+	}
+
+	return state
+}
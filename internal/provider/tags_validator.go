@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ validator.List = tagsValidator{}
+
+// tagsValidator rejects empty and duplicate tags, reporting each offending element
+// by its index.
+type tagsValidator struct{}
+
+func (v tagsValidator) Description(ctx context.Context) string {
+	return "tags must be non-empty and unique"
+}
+
+func (v tagsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v tagsValidator) ValidateList(ctx context.Context, req validator.ListRequest, resp *validator.ListResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	seen := map[string]int{}
+	for i, element := range req.ConfigValue.Elements() {
+		tag, ok := element.(types.String)
+		if !ok || tag.IsNull() || tag.IsUnknown() {
+			continue
+		}
+
+		if strings.TrimSpace(tag.ValueString()) == "" {
+			resp.Diagnostics.AddAttributeError(
+				req.Path.AtListIndex(i),
+				"Invalid tag",
+				fmt.Sprintf("Tag at index %d is empty.", i),
+			)
+			continue
+		}
+
+		if first, ok := seen[tag.ValueString()]; ok {
+			resp.Diagnostics.AddAttributeError(
+				req.Path.AtListIndex(i),
+				"Duplicate tag",
+				fmt.Sprintf("Tag %q at index %d duplicates the tag at index %d.", tag.ValueString(), i, first),
+			)
+			continue
+		}
+		seen[tag.ValueString()] = i
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestTagsValidator(t *testing.T) {
+	cases := map[string]struct {
+		tags           []string
+		expectedErrors int
+	}{
+		"valid":     {tags: []string{"a", "b"}},
+		"empty":     {tags: []string{"a", ""}, expectedErrors: 1},
+		"blank":     {tags: []string{" "}, expectedErrors: 1},
+		"duplicate": {tags: []string{"a", "b", "a", "a"}, expectedErrors: 2},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			value, diags := types.ListValueFrom(context.Background(), types.StringType, tc.tags)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			req := validator.ListRequest{Path: path.Root("tags"), ConfigValue: value}
+			var resp validator.ListResponse
+			tagsValidator{}.ValidateList(context.Background(), req, &resp)
+
+			if resp.Diagnostics.ErrorsCount() != tc.expectedErrors {
+				t.Errorf("expected %d errors, got: %v", tc.expectedErrors, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// tokenCommandTTL is how long a fetched token is cached when its expiry cannot be
+// read from the token itself or the identity service response.
+const tokenCommandTTL = 5 * time.Minute
+
+// tokenExpiryMargin is how long before its expiry a token is refreshed.
+const tokenExpiryMargin = time.Minute
+
+// tokenSource provides the bearer token sent with each request. It either holds
+// a static token or fetches a fresh one, by running a command or exchanging an
+// application credential, whenever the cached token is about to expire.
+type tokenSource struct {
+	mu     sync.Mutex
+	fetch  func(ctx context.Context) (string, time.Time, error)
+	token  string
+	expiry time.Time
+}
+
+func newStaticTokenSource(token string) *tokenSource {
+	return &tokenSource{token: token}
+}
+
+func newCommandTokenSource(command []string) *tokenSource {
+	return &tokenSource{fetch: func(ctx context.Context) (string, time.Time, error) {
+		return runTokenCommand(ctx, command)
+	}}
+}
+
+func newApplicationCredentialTokenSource(authURL, id, secret string) *tokenSource {
+	return &tokenSource{fetch: func(ctx context.Context) (string, time.Time, error) {
+		return fetchApplicationCredentialToken(ctx, authURL, id, secret)
+	}}
+}
+
+// Token returns a valid bearer token, fetching a new one if needed.
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fetch == nil || (s.token != "" && time.Now().Before(s.expiry)) {
+		return s.token, nil
+	}
+
+	token, expiry, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	s.token = token
+	s.expiry = expiry
+
+	return s.token, nil
+}
+
+// runTokenCommand runs command and returns the token it prints along with the
+// time at which it should be refreshed.
+func runTokenCommand(ctx context.Context, command []string) (string, time.Time, error) {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("token_command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	token := strings.TrimSpace(string(output))
+	if token == "" {
+		return "", time.Time{}, fmt.Errorf("token_command returned an empty token")
+	}
+
+	refreshAt := time.Now().Add(tokenCommandTTL)
+	if expiry, ok := jwtExpiry(token); ok {
+		refreshAt = expiry.Add(-tokenExpiryMargin)
+	}
+
+	return token, refreshAt, nil
+}
+
+// fetchApplicationCredentialToken exchanges an OpenStack application credential
+// for a token with the identity service (Keystone v3) at authURL, and returns it
+// along with the time at which it should be refreshed.
+func fetchApplicationCredentialToken(ctx context.Context, authURL, id, secret string) (string, time.Time, error) {
+	var body struct {
+		Auth struct {
+			Identity struct {
+				Methods               []string `json:"methods"`
+				ApplicationCredential struct {
+					Id     string `json:"id"`
+					Secret string `json:"secret"`
+				} `json:"application_credential"`
+			} `json:"identity"`
+		} `json:"auth"`
+	}
+	body.Auth.Identity.Methods = []string{"application_credential"}
+	body.Auth.Identity.ApplicationCredential.Id = id
+	body.Auth.Identity.ApplicationCredential.Secret = secret
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(authURL, "/")+"/auth/tokens", bytes.NewReader(payload))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("application credential authentication failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("application credential authentication failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", time.Time{}, fmt.Errorf("application credential authentication failed: %w", newResponseAPIError(resp, respBody))
+	}
+
+	token := resp.Header.Get("X-Subject-Token")
+	if token == "" {
+		return "", time.Time{}, fmt.Errorf("application credential authentication failed: no X-Subject-Token in response")
+	}
+
+	refreshAt := time.Now().Add(tokenCommandTTL)
+	var result struct {
+		Token struct {
+			ExpiresAt time.Time `json:"expires_at"`
+		} `json:"token"`
+	}
+	if err := json.Unmarshal(respBody, &result); err == nil && !result.Token.ExpiresAt.IsZero() {
+		refreshAt = result.Token.ExpiresAt.Add(-tokenExpiryMargin)
+	}
+
+	return token, refreshAt, nil
+}
+
+// bearerTokenError describes what is wrong with a bearer token configured on the
+// provider, or returns "" when it looks usable. Opaque tokens (e.g. Keystone tokens)
+// are accepted as is, JWTs are additionally checked for expiry.
+func bearerTokenError(token string, now time.Time) string {
+	if strings.TrimSpace(token) == "" {
+		return "The bearer token is empty, every request would be rejected with 401."
+	}
+	if strings.ContainsFunc(token, unicode.IsSpace) {
+		return "The bearer token contains whitespace. Set the token alone, without the \"Bearer \" prefix or a trailing newline (e.g. use trimspace(file(...)))."
+	}
+	if expiry, ok := jwtExpiry(token); ok && !now.Before(expiry) {
+		return fmt.Sprintf("The bearer token expired at %s, fetch a new one or use token_command to have the provider refresh it.", expiry.UTC().Format(time.RFC3339))
+	}
+
+	return ""
+}
+
+// jwtExpiry returns the expiry (exp claim) of a JWT. It doesn't verify the token.
+func jwtExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(claims.Exp, 0), true
+}
@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJWTExpiry(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"user","exp":1700000000}`))
+
+	expiry, ok := jwtExpiry("header." + payload + ".signature")
+	if !ok || !expiry.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("expected expiry 1700000000, got %v (ok: %t)", expiry.Unix(), ok)
+	}
+
+	if _, ok := jwtExpiry("opaque-token"); ok {
+		t.Error("expected no expiry for an opaque token")
+	}
+}
+
+func TestBearerTokenError(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	jwt := func(exp int64) string {
+		payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp)))
+		return "header." + payload + ".signature"
+	}
+
+	cases := map[string]struct {
+		token       string
+		expectError bool
+	}{
+		"opaque":        {token: "gAAAAABk-opaque"},
+		"valid jwt":     {token: jwt(1700000000 + 60)},
+		"empty":         {token: "", expectError: true},
+		"blank":         {token: "  ", expectError: true},
+		"bearer prefix": {token: "Bearer abc", expectError: true},
+		"newline":       {token: "abc\n", expectError: true},
+		"expired jwt":   {token: jwt(1700000000 - 60), expectError: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := bearerTokenError(tc.token, now); (got != "") != tc.expectError {
+				t.Errorf("expected error %t, got %q", tc.expectError, got)
+			}
+		})
+	}
+}
+
+func TestCommandTokenSource(t *testing.T) {
+	tokens := newCommandTokenSource([]string{"echo", "my-token"})
+
+	token, err := tokens.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "my-token" {
+		t.Errorf("expected my-token, got %q", token)
+	}
+	if time.Until(tokens.expiry) > tokenCommandTTL {
+		t.Errorf("expected opaque token to be cached for at most %s", tokenCommandTTL)
+	}
+
+	if _, err := newCommandTokenSource([]string{"false"}).Token(context.Background()); err == nil {
+		t.Error("expected an error for a failing command")
+	}
+}
+
+func TestApplicationCredentialTokenSource(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Auth struct {
+				Identity struct {
+					ApplicationCredential struct {
+						Id     string `json:"id"`
+						Secret string `json:"secret"`
+					} `json:"application_credential"`
+				} `json:"identity"`
+			} `json:"auth"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		credential := body.Auth.Identity.ApplicationCredential
+		if r.URL.Path != "/v3/auth/tokens" || credential.Id != "id" || credential.Secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": {"message": "The request you have made requires authentication."}}`))
+			return
+		}
+		w.Header().Set("X-Subject-Token", "keystone-token")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token": {"expires_at": "` + expiresAt.Format("2006-01-02T15:04:05.000000Z") + `"}}`))
+	}))
+	defer server.Close()
+
+	tokens := newApplicationCredentialTokenSource(server.URL+"/v3/", "id", "secret")
+	token, err := tokens.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "keystone-token" {
+		t.Errorf("expected keystone-token, got %q", token)
+	}
+	if !tokens.expiry.Equal(expiresAt.Add(-tokenExpiryMargin)) {
+		t.Errorf("expected token to be refreshed at %s, got %s", expiresAt.Add(-tokenExpiryMargin), tokens.expiry)
+	}
+
+	if _, err := newApplicationCredentialTokenSource(server.URL+"/v3", "id", "wrong").Token(context.Background()); err == nil {
+		t.Error("expected an error for a rejected credential")
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/time/rate"
+)
+
+// defaultMaxRetries is used when the provider max_retries attribute is unset.
+const defaultMaxRetries = 3
+
+// maxRetryDelay caps the wait between two attempts, including server provided Retry-After values.
+const maxRetryDelay = 60 * time.Second
+
+// retryTransport retries idempotent requests that fail with a transient status
+// code (429, 502, 503, 504), honoring the Retry-After response header.
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || attempt >= t.maxRetries || !isRetryableRequest(req) || !isRetryableStatus(resp.StatusCode) {
+			return resp, err
+		}
+
+		delay := retryDelay(resp, attempt)
+		tflog.Debug(req.Context(), fmt.Sprintf("Retrying %s %s after HTTP %d in %s", req.Method, req.URL.Path, resp.StatusCode, delay))
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// rateLimitTransport throttles every request sent to the Strato API, including
+// the retries of retryTransport, to the rate allowed by limiter.
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// isRetryableRequest reports whether req is idempotent and can be sent again.
+func isRetryableRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	default:
+		return false
+	}
+}
+
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay returns the delay requested by the Retry-After header, falling back
+// to an exponential backoff of 1s, 2s, 4s, ... when the header is absent or invalid.
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	delay := time.Second << attempt
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			delay = time.Duration(seconds) * time.Second
+		} else if date, err := http.ParseTime(retryAfter); err == nil {
+			delay = max(time.Until(date), 0)
+		}
+	}
+
+	return min(delay, maxRetryDelay)
+}
+
+// loggingTransport logs the status code and the redacted body of every response,
+// which request editors cannot see. Bodies too long to be redacted are not logged.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		tflog.Debug(req.Context(), fmt.Sprintf("HTTP Response:\n  %s %s failed: %s\n", req.Method, req.URL.String(), err))
+		return resp, err
+	}
+
+	var msg strings.Builder
+	msg.WriteString("HTTP Response:\n")
+	msg.WriteString("  Request: " + req.Method + " " + req.URL.String() + "\n")
+	msg.WriteString("  Status: " + resp.Status + "\n")
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		msg.WriteString("  Content-Type: " + contentType + "\n")
+	}
+
+	if resp.Body != nil {
+		// Only read what may be logged, the SDK still decodes the body in full
+		prefix, body, err := peekBody(resp.Body, maxLoggedBodyLength)
+		resp.Body = body
+		if err == nil && len(prefix) > 0 {
+			msg.WriteString("  Body: " + redactBodyPrefix(prefix, maxLoggedBodyLength) + "\n")
+		}
+	}
+
+	tflog.Debug(req.Context(), msg.String())
+
+	return resp, nil
+}
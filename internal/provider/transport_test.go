@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	cases := map[string]struct {
+		method        string
+		statuses      []int
+		maxRetries    int
+		expectedCalls int
+		expectedCode  int
+	}{
+		"retries transient status": {
+			method:        http.MethodGet,
+			statuses:      []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			maxRetries:    3,
+			expectedCalls: 3,
+			expectedCode:  http.StatusOK,
+		},
+		"gives up after max retries": {
+			method:        http.MethodDelete,
+			statuses:      []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			maxRetries:    1,
+			expectedCalls: 2,
+			expectedCode:  http.StatusBadGateway,
+		},
+		"does not retry non-idempotent requests": {
+			method:        http.MethodPost,
+			statuses:      []int{http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries:    3,
+			expectedCalls: 1,
+			expectedCode:  http.StatusServiceUnavailable,
+		},
+		"does not retry other errors": {
+			method:        http.MethodGet,
+			statuses:      []int{http.StatusInternalServerError, http.StatusOK},
+			maxRetries:    3,
+			expectedCalls: 1,
+			expectedCode:  http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(tc.statuses[calls])
+				calls++
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: &retryTransport{next: http.DefaultTransport, maxRetries: tc.maxRetries}}
+			req, err := http.NewRequest(tc.method, server.URL, strings.NewReader("{}"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if calls != tc.expectedCalls {
+				t.Errorf("expected %d calls, got %d", tc.expectedCalls, calls)
+			}
+			if resp.StatusCode != tc.expectedCode {
+				t.Errorf("expected status %d, got %d", tc.expectedCode, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	cases := map[string]struct {
+		retryAfter string
+		attempt    int
+		expected   time.Duration
+	}{
+		"seconds":        {retryAfter: "5", attempt: 0, expected: 5 * time.Second},
+		"backoff":        {retryAfter: "", attempt: 2, expected: 4 * time.Second},
+		"invalid header": {retryAfter: "soon", attempt: 1, expected: 2 * time.Second},
+		"capped":         {retryAfter: "3600", attempt: 0, expected: maxRetryDelay},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tc.retryAfter != "" {
+				resp.Header.Set("Retry-After", tc.retryAfter)
+			}
+			if got := retryDelay(resp, tc.attempt); got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoggingTransport(t *testing.T) {
+	cases := map[string]struct {
+		body string
+	}{
+		"short body": {body: `{"name":"prod","keypair":"ops"}`},
+		"long body":  {body: `{"name":"` + strings.Repeat("a", 4*maxLoggedBodyLength) + `"}`},
+		"empty body": {body: ""},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: &loggingTransport{next: http.DefaultTransport}}
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			defer resp.Body.Close()
+
+			// The body is passed on in full, whatever part of it was logged
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if string(body) != tc.body {
+				t.Errorf("expected the body to be passed on unchanged, got %d bytes instead of %d", len(body), len(tc.body))
+			}
+		})
+	}
+}